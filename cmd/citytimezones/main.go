@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/richoandika/city-timezones-go/pkg/citytimezones"
 )
@@ -23,6 +24,8 @@ func main() {
 		cityName     = flag.String("city", "", "Search by city name")
 		searchString = flag.String("search", "", "Search by city, state, province, or country")
 		isoCode      = flag.String("iso", "", "Search by ISO2 or ISO3 country code")
+		airport      = flag.String("airport", "", "Search by IATA or ICAO airport code")
+		province     = flag.String("province", "", "Search by province or state (combines with -city and -iso)")
 		timezone     = flag.String("timezone", "", "Filter by timezone")
 		country      = flag.String("country", "", "Filter by country")
 		output       = flag.String("output", "table", "Output format: table, json")
@@ -52,12 +55,20 @@ func main() {
 	var results []citytimezones.CityData
 
 	// Perform search based on flags
-	if *cityName != "" {
+	if *province != "" {
+		results, err = citytimezones.FindCities(citytimezones.CityQuery{
+			City:     *cityName,
+			Province: *province,
+			ISO2:     *isoCode,
+		})
+	} else if *cityName != "" {
 		results, err = citytimezones.LookupViaCity(*cityName)
 	} else if *searchString != "" {
 		results, err = citytimezones.FindFromCityStateProvince(*searchString)
 	} else if *isoCode != "" {
 		results, err = citytimezones.FindFromIsoCode(*isoCode)
+	} else if *airport != "" {
+		results, err = citytimezones.LookupViaAirportCode(*airport)
 	} else {
 		// No search criteria provided, show all cities
 		results = allCities
@@ -93,6 +104,17 @@ func showVersion() {
 	fmt.Printf("Version:    %s\n", version)
 	fmt.Printf("Commit:     %s\n", commit)
 	fmt.Printf("Build Date: %s\n", date)
+
+	if info, err := citytimezones.DatasetInfo(); err == nil {
+		fmt.Println()
+		fmt.Printf("Dataset:    %s (%d cities", info.Version, info.Cities)
+		if info.Lite {
+			fmt.Print(", lite")
+		}
+		fmt.Println(")")
+		fmt.Printf("Generated:  %s from commit %s\n", info.GeneratedAt.Format(time.RFC3339), info.Commit)
+		fmt.Printf("Source:     %s (%s)\n", info.Source, info.SourceURL)
+	}
 	fmt.Println()
 	fmt.Println("https://github.com/richoandika/city-timezones-go")
 }
@@ -111,6 +133,10 @@ func showHelp() {
 	fmt.Println("        Search by city, state, province, or country")
 	fmt.Println("  -iso string")
 	fmt.Println("        Search by ISO2 or ISO3 country code")
+	fmt.Println("  -airport string")
+	fmt.Println("        Search by IATA or ICAO airport code")
+	fmt.Println("  -province string")
+	fmt.Println("        Search by province or state (combines with -city and -iso)")
 	fmt.Println()
 	fmt.Println("Filter Options:")
 	fmt.Println("  -timezone string")
@@ -135,6 +161,8 @@ func showHelp() {
 	fmt.Println("  citytimezones -city Chicago")
 	fmt.Println("  citytimezones -search 'springfield mo'")
 	fmt.Println("  citytimezones -iso DE -limit 5")
+	fmt.Println("  citytimezones -airport ORD")
+	fmt.Println("  citytimezones -city Springfield -province MO")
 	fmt.Println("  citytimezones -timezone 'America/New_York' -output json")
 }
 
@@ -0,0 +1,15 @@
+//go:build !citytz_lite
+
+package data
+
+import (
+	_ "embed" // Required for go:embed
+)
+
+// Lite reports whether the lite dataset is embedded
+const Lite = false
+
+// CityMap is the gzip-compressed city dataset
+//
+//go:embed cityMap.json.gz
+var CityMap []byte
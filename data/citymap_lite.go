@@ -0,0 +1,16 @@
+//go:build citytz_lite
+
+package data
+
+import (
+	_ "embed" // Required for go:embed
+)
+
+// Lite reports whether the lite dataset is embedded
+const Lite = true
+
+// CityMap is the gzip-compressed city dataset, limited to cities of at least
+// LiteMinPopulation inhabitants
+//
+//go:embed cityMapLite.json.gz
+var CityMap []byte
@@ -0,0 +1,53 @@
+// Package data embeds the city dataset bundled with the library.
+//
+// cityMap.json is the source of the dataset. It is embedded gzip-compressed
+// as cityMap.json.gz, or as cityMapLite.json.gz with only the cities of at
+// least LiteMinPopulation inhabitants when building with the citytz_lite tag.
+// Both files, and the dataset metadata in metadata.json, are generated from
+// cityMap.json with go generate.
+package data
+
+import (
+	_ "embed" // Required for go:embed
+	"time"
+)
+
+//go:generate go run ../tools/gendata -dir .
+
+// LiteMinPopulation is the population a city needs to be included in the
+// dataset embedded by the citytz_lite build
+const LiteMinPopulation = 100000
+
+// AlternateNames is the supplemental alternateNames.json file
+//
+//go:embed alternateNames.json
+var AlternateNames []byte
+
+// Airports is the supplemental airports.json file mapping IATA and ICAO
+// airport codes to the cities they serve
+//
+//go:embed airports.json
+var Airports []byte
+
+// CityArea is the supplemental cityArea.json file
+//
+//go:embed cityArea.json
+var CityArea []byte
+
+// Metadata describes the embedded dataset. It is written to metadata.json
+// when the dataset is generated.
+type Metadata struct {
+	Version           string    `json:"version"`    // Hash of cityMap.json
+	Commit            string    `json:"commit"`     // Repository commit the dataset was generated from
+	Source            string    `json:"source"`     // Attribution of the data
+	SourceURL         string    `json:"source_url"` // Where the data comes from
+	GeneratedAt       time.Time `json:"generated_at"`
+	Cities            int       `json:"cities"`      // Records in cityMap.json.gz
+	LiteCities        int       `json:"lite_cities"` // Records in cityMapLite.json.gz
+	LiteMinPopulation int       `json:"lite_min_population"`
+}
+
+// MetadataJSON is the generated metadata.json file
+//
+//go:embed metadata.json
+var MetadataJSON []byte
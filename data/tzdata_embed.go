@@ -0,0 +1,10 @@
+//go:build citytz_tzdata
+
+package data
+
+import (
+	_ "time/tzdata" // Embeds the IANA timezone database in the binary
+)
+
+// TZData reports whether the timezone database is embedded
+const TZData = true
@@ -0,0 +1,6 @@
+//go:build !citytz_tzdata
+
+package data
+
+// TZData reports whether the timezone database is embedded
+const TZData = false
@@ -0,0 +1,107 @@
+package city
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/richoandika/city-timezones-go/data"
+)
+
+// airportEntry is a record of the supplemental data/airports.json file
+type airportEntry struct {
+	supplementRef
+	IATA string `json:"iata"`
+	ICAO string `json:"icao"`
+	Name string `json:"name"`
+}
+
+var (
+	airportsByCode map[string]airportEntry // Uppercase IATA and ICAO codes
+	airportsOnce   sync.Once
+	airportsErr    error
+)
+
+// loadAirports parses the embedded airport table on first use
+func loadAirports() (map[string]airportEntry, error) {
+	airportsOnce.Do(func() {
+		airportsByCode, airportsErr = parseAirports(data.Airports)
+	})
+	return airportsByCode, airportsErr
+}
+
+// parseAirports indexes airport records by their IATA and ICAO codes
+func parseAirports(raw []byte) (map[string]airportEntry, error) {
+	var entries []airportEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, NewDataLoadError("load airports", fmt.Errorf("failed to unmarshal supplemental data file airports.json: %w", err))
+	}
+
+	byCode := make(map[string]airportEntry, 2*len(entries))
+	for _, entry := range entries {
+		for _, code := range []string{entry.IATA, entry.ICAO} {
+			if code != "" {
+				byCode[strings.ToUpper(code)] = entry
+			}
+		}
+	}
+
+	return byCode, nil
+}
+
+// LookupViaAirportCode returns the city served by the airport with the given
+// IATA ("ORD") or ICAO ("KORD") code. Airports are resolved against the
+// client's current dataset, so a custom dataset without the city yields no results.
+func (c *Client) LookupViaAirportCode(code string) ([]CityData, error) {
+	return c.LookupViaAirportCodeContext(context.Background(), code)
+}
+
+// LookupViaAirportCodeContext is like LookupViaAirportCode but passes ctx to
+// the client's hooks and provider
+func (c *Client) LookupViaAirportCodeContext(ctx context.Context, code string) ([]CityData, error) {
+	trace := c.startLookup(ctx, "LookupViaAirportCode", code)
+	results, err := c.lookupViaAirportCode(trace.ctx, code)
+	trace.end(len(results), false, err)
+	return results, err
+}
+
+// lookupViaAirportCode implements LookupViaAirportCode
+func (c *Client) lookupViaAirportCode(ctx context.Context, code string) ([]CityData, error) {
+	validatedCode, err := ValidateAirportCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid airport code: %w", err)
+	}
+
+	if validatedCode == "" {
+		if err := c.emptyInputError("airportCode"); err != nil {
+			return nil, err
+		}
+		return []CityData{}, nil
+	}
+
+	airports, err := loadAirports()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CityData
+	if airport, ok := airports[validatedCode]; ok {
+		candidates, err := c.source().ByName(ctx, airport.City)
+		if err != nil {
+			return nil, err
+		}
+		for _, city := range candidates {
+			if airport.matches(city) {
+				results = append(results, city)
+			}
+		}
+	}
+
+	if err := c.notFoundError(validatedCode, "lookup by airport code", len(results)); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
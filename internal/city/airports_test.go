@@ -0,0 +1,86 @@
+package city
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupViaAirportCode(t *testing.T) {
+	t.Run("IATA and ICAO codes", func(t *testing.T) {
+		for _, code := range []string{"ORD", "KORD", "ord", " kord "} {
+			cities, err := LookupViaAirportCode(code)
+			if err != nil {
+				t.Fatalf("%s: should not error: %v", code, err)
+			}
+			if len(cities) != 1 || cities[0].City != "Chicago" || cities[0].Timezone != "America/Chicago" {
+				t.Errorf("%s: expected Chicago, got %v", code, cities)
+			}
+		}
+	})
+
+	t.Run("Province disambiguates", func(t *testing.T) {
+		cities, err := LookupViaAirportCode("LAS")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].Province != "Nevada" {
+			t.Errorf("Expected Las Vegas, Nevada, got %v", cities)
+		}
+	})
+
+	t.Run("Every airport resolves to one city", func(t *testing.T) {
+		airports, err := loadAirports()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		for code, airport := range airports {
+			cities, err := LookupViaAirportCode(code)
+			if err != nil || len(cities) != 1 {
+				t.Errorf("%s (%s): expected 1 city, got %d, %v", code, airport.Name, len(cities), err)
+			}
+		}
+	})
+
+	t.Run("Unknown code", func(t *testing.T) {
+		cities, err := LookupViaAirportCode("XXX")
+		if err != nil || len(cities) != 0 {
+			t.Errorf("Expected no results, got %v, %v", cities, err)
+		}
+
+		client := NewClient(ClientOptions{ErrorOnNotFound: true})
+		if _, err := client.LookupViaAirportCode("XXX"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Invalid code", func(t *testing.T) {
+		for _, code := range []string{"OR", "KORDX", "O1D"} {
+			if _, err := LookupViaAirportCode(code); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("%s: expected ErrInvalidInput, got %v", code, err)
+			}
+		}
+	})
+
+	t.Run("Blank code", func(t *testing.T) {
+		for _, code := range []string{"", "   "} {
+			if cities, err := LookupViaAirportCode(code); err != nil || len(cities) != 0 {
+				t.Errorf("%q: expected no results and no error, got %v, %v", code, cities, err)
+			}
+		}
+
+		client := NewClient(ClientOptions{ErrorOnEmptyInput: true})
+		if _, err := client.LookupViaAirportCode("   "); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput with ErrorOnEmptyInput, got %v", err)
+		}
+	})
+
+	t.Run("Custom dataset", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{{City: "Chicago", ISO2: "US", Timezone: "America/Chicago"}}})
+		if cities, _ := client.LookupViaAirportCode("ORD"); len(cities) != 1 {
+			t.Errorf("Expected Chicago, got %d", len(cities))
+		}
+		if cities, _ := client.LookupViaAirportCode("LHR"); len(cities) != 0 {
+			t.Errorf("Expected no results, got %d", len(cities))
+		}
+	})
+}
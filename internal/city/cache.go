@@ -1,8 +1,9 @@
 package city
 
 import (
-	"container/list"
+	"reflect"
 	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -10,21 +11,58 @@ const (
 	DefaultMaxCacheSize = 1000
 )
 
+// resultSizeBounds are the inclusive upper bounds, in number of cities, of the
+// result size histogram buckets. A final bucket counts larger results.
+var resultSizeBounds = []int{0, 1, 5, 20, 100, 1000}
+
+// cityDataSize is the in-memory size of a CityData value, excluding the
+// string and slice contents it references
+var cityDataSize = uint64(reflect.TypeOf(CityData{}).Size())
+
+const (
+	// maxCacheShards is the maximum number of shards of a SearchCache
+	maxCacheShards = 16
+
+	// minShardSize is the minimum capacity of a shard. Shards only pay off
+	// with many cores contending for one cache; on a single core a sharded
+	// cache is slower, so caches below 2048 entries, including the default
+	// size, use a single shard and evict close to LRU.
+	minShardSize = 1024
+)
+
 // cacheEntry represents a single cache entry with its key
 type cacheEntry struct {
-	key   string
-	value []CityData
+	key        string
+	value      []CityData
+	referenced atomic.Bool // Set on hits, cleared as the clock hand passes
 }
 
-// SearchCache provides thread-safe caching for search results with LRU eviction
+// cacheShard is a part of a SearchCache. It evicts with the CLOCK algorithm,
+// an approximation of LRU: hits only set the entry's referenced flag, so they
+// need no more than a read lock, and the clock hand gives referenced entries
+// a second chance before evicting them.
+type cacheShard struct {
+	mu      sync.RWMutex
+	entries map[string]*cacheEntry
+	ring    []*cacheEntry // Entries in insertion order, starting at hand once full
+	hand    int
+	maxSize int
+
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	hitBytes    atomic.Uint64
+	resultSizes []atomic.Uint64
+
+	_ [64]byte // Keeps the counters of neighboring shards on separate cache lines
+}
+
+// SearchCache provides thread-safe caching for search results. Hits take only
+// a read lock, and large caches spread keys across shards with their own
+// locks. Eviction approximates LRU.
 type SearchCache struct {
-	mu        sync.RWMutex
-	cache     map[string]*list.Element
-	lruList   *list.List
-	maxSize   int
-	hits      uint64
-	misses    uint64
-	evictions uint64
+	shards  []*cacheShard
+	maxSize int
 }
 
 // NewSearchCache creates a new search cache with default max size
@@ -37,113 +75,196 @@ func NewSearchCacheWithSize(maxSize int) *SearchCache {
 	if maxSize <= 0 {
 		maxSize = DefaultMaxCacheSize
 	}
-	return &SearchCache{
-		cache:   make(map[string]*list.Element),
-		lruList: list.New(),
+
+	shards := 1
+	for shards < maxCacheShards && maxSize/(shards*2) >= minShardSize {
+		shards *= 2
+	}
+	return newSearchCache(maxSize, shards)
+}
+
+// newSearchCache creates a cache with the given number of shards, a power of
+// two, splitting maxSize between them
+func newSearchCache(maxSize, shards int) *SearchCache {
+	c := &SearchCache{
+		shards:  make([]*cacheShard, shards),
 		maxSize: maxSize,
 	}
+
+	for i := range c.shards {
+		size := maxSize / shards
+		if i < maxSize%shards {
+			size++
+		}
+		c.shards[i] = &cacheShard{
+			entries:     make(map[string]*cacheEntry),
+			maxSize:     size,
+			resultSizes: make([]atomic.Uint64, len(resultSizeBounds)+1),
+		}
+	}
+
+	return c
+}
+
+// shard returns the shard holding key, chosen by its FNV-1a hash
+func (c *SearchCache) shard(key string) *cacheShard {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+
+	hash := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= 16777619
+	}
+	return c.shards[hash&uint32(len(c.shards)-1)]
 }
 
-// Get retrieves a cached result and updates LRU order
+// Get retrieves a cached result and marks it as recently used
 func (c *SearchCache) Get(key string) ([]CityData, bool) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	s := c.shard(key)
+
+	s.mu.RLock()
+	entry, exists := s.entries[key]
+	var value []CityData
+	if exists {
+		value = entry.value
+		if !entry.referenced.Load() {
+			entry.referenced.Store(true)
+		}
+	}
+	s.mu.RUnlock()
 
-	element, exists := c.cache[key]
 	if !exists {
-		c.misses++
+		s.misses.Add(1)
 		return nil, false
 	}
 
-	// Move to front (most recently used)
-	c.lruList.MoveToFront(element)
-	c.hits++
+	s.hits.Add(1)
+	s.recordResultSize(len(value))
+	return value, true
+}
+
+// recordResultSize tracks the size of a result returned on a cache hit.
+// Results are returned by reference, so hitBytes is what copying them on
+// every hit would cost.
+func (s *cacheShard) recordResultSize(size int) {
+	s.hitBytes.Add(uint64(size) * cityDataSize)
 
-	entry := element.Value.(*cacheEntry)
-	return entry.value, true
+	bucket := len(resultSizeBounds)
+	for i, bound := range resultSizeBounds {
+		if size <= bound {
+			bucket = i
+			break
+		}
+	}
+	s.resultSizes[bucket].Add(1)
 }
 
-// Set stores a result in the cache with LRU eviction
+// Set stores a result in the cache, evicting an entry if the shard is full
 func (c *SearchCache) Set(key string, result []CityData) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Check if key already exists
-	if element, exists := c.cache[key]; exists {
-		// Update existing entry and move to front
-		c.lruList.MoveToFront(element)
-		entry := element.Value.(*cacheEntry)
+	s := c.shard(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Update an existing entry and mark it as recently used
+	if entry, exists := s.entries[key]; exists {
 		entry.value = result
+		entry.referenced.Store(true)
 		return
 	}
 
-	// Add new entry
-	entry := &cacheEntry{
-		key:   key,
-		value: result,
-	}
-	element := c.lruList.PushFront(entry)
-	c.cache[key] = element
+	entry := &cacheEntry{key: key, value: result}
+	s.entries[key] = entry
 
-	// Evict least recently used if over capacity
-	if c.lruList.Len() > c.maxSize {
-		c.evictOldest()
+	if len(s.ring) < s.maxSize {
+		s.ring = append(s.ring, entry)
+		return
 	}
+	s.evict(entry)
 }
 
-// evictOldest removes the least recently used entry (must be called with lock held)
-func (c *SearchCache) evictOldest() {
-	oldest := c.lruList.Back()
-	if oldest != nil {
-		c.lruList.Remove(oldest)
-		entry := oldest.Value.(*cacheEntry)
-		delete(c.cache, entry.key)
-		c.evictions++
+// evict replaces the first unreferenced entry from the clock hand on with
+// entry, clearing the referenced flags it passes (must be called with lock held)
+func (s *cacheShard) evict(entry *cacheEntry) {
+	for {
+		candidate := s.ring[s.hand]
+		if candidate.referenced.Load() {
+			candidate.referenced.Store(false)
+			s.hand = (s.hand + 1) % len(s.ring)
+			continue
+		}
+
+		delete(s.entries, candidate.key)
+		s.ring[s.hand] = entry
+		s.hand = (s.hand + 1) % len(s.ring)
+		s.evictions.Add(1)
+		return
 	}
 }
 
 // Clear clears the cache
 func (c *SearchCache) Clear() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.cache = make(map[string]*list.Element)
-	c.lruList = list.New()
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.entries = make(map[string]*cacheEntry)
+		s.ring = nil
+		s.hand = 0
+		s.mu.Unlock()
+	}
 	// Note: We don't reset statistics on clear
 }
 
 // Size returns the number of cached entries
 func (c *SearchCache) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return len(c.cache)
+	size := 0
+	for _, s := range c.shards {
+		s.mu.RLock()
+		size += len(s.entries)
+		s.mu.RUnlock()
+	}
+	return size
 }
 
 // MaxSize returns the maximum cache size
 func (c *SearchCache) MaxSize() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
 	return c.maxSize
 }
 
 // Stats returns cache statistics
 func (c *SearchCache) Stats() CacheStats {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	stats := CacheStats{
+		Size:        c.Size(),
+		MaxSize:     c.maxSize,
+		ResultSizes: make([]ResultSizeBucket, len(resultSizeBounds)+1),
+	}
+
+	for i := range stats.ResultSizes {
+		stats.ResultSizes[i].UpperBound = -1
+		if i < len(resultSizeBounds) {
+			stats.ResultSizes[i].UpperBound = resultSizeBounds[i]
+		}
+	}
 
-	var hitRate float64
-	total := c.hits + c.misses
-	if total > 0 {
-		hitRate = float64(c.hits) / float64(total) * 100
+	for _, s := range c.shards {
+		stats.Hits += s.hits.Load()
+		stats.Misses += s.misses.Load()
+		stats.Evictions += s.evictions.Load()
+		stats.HitBytes += s.hitBytes.Load()
+		for i := range s.resultSizes {
+			stats.ResultSizes[i].Count += s.resultSizes[i].Load()
+		}
 	}
 
-	return CacheStats{
-		Size:      len(c.cache),
-		MaxSize:   c.maxSize,
-		Hits:      c.hits,
-		Misses:    c.misses,
-		Evictions: c.evictions,
-		HitRate:   hitRate,
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total) * 100
 	}
+	if stats.Hits > 0 {
+		stats.BytesPerHit = float64(stats.HitBytes) / float64(stats.Hits)
+	}
+
+	return stats
 }
 
 // CacheStats contains cache performance statistics
@@ -154,6 +275,19 @@ type CacheStats struct {
 	Misses    uint64  // Number of cache misses
 	Evictions uint64  // Number of evictions due to size limit
 	HitRate   float64 // Cache hit rate as percentage
+
+	// Cached results are returned by reference. HitBytes is the number of
+	// bytes copying them on every hit would cost (CityData values only, the
+	// strings they reference are shared either way).
+	HitBytes    uint64
+	BytesPerHit float64            // Average of HitBytes per hit
+	ResultSizes []ResultSizeBucket // Histogram of result sizes returned on hits
+}
+
+// ResultSizeBucket is a result size histogram bucket
+type ResultSizeBucket struct {
+	UpperBound int    // Inclusive upper bound in number of cities, -1 for the overflow bucket
+	Count      uint64 // Number of hits that returned a result of this size
 }
 
 // Global cache instance
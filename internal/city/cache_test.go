@@ -1,6 +1,8 @@
 package city
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -274,3 +276,183 @@ func TestCacheMaxSize(t *testing.T) {
 		}
 	})
 }
+
+func TestSearchCacheResultSizeStats(t *testing.T) {
+	t.Run("Histogram and copy cost", func(t *testing.T) {
+		cache := NewSearchCache()
+		cache.Set("empty", []CityData{})
+		cache.Set("one", []CityData{{City: "Chicago"}})
+		cache.Set("many", make([]CityData, 50))
+
+		cache.Get("empty")
+		cache.Get("one")
+		cache.Get("one")
+		cache.Get("many")
+		cache.Get("missing")
+
+		stats := cache.Stats()
+		if stats.HitBytes != 52*cityDataSize {
+			t.Errorf("expected %d hit bytes, got %d", 52*cityDataSize, stats.HitBytes)
+		}
+		if stats.BytesPerHit != float64(52*cityDataSize)/4 {
+			t.Errorf("expected %f bytes per hit, got %f", float64(52*cityDataSize)/4, stats.BytesPerHit)
+		}
+
+		expected := map[int]uint64{0: 1, 1: 2, 100: 1}
+		for _, bucket := range stats.ResultSizes {
+			if bucket.Count != expected[bucket.UpperBound] {
+				t.Errorf("bucket <= %d: expected %d, got %d", bucket.UpperBound, expected[bucket.UpperBound], bucket.Count)
+			}
+		}
+		if last := stats.ResultSizes[len(stats.ResultSizes)-1]; last.UpperBound != -1 {
+			t.Errorf("last bucket should be the overflow bucket, got bound %d", last.UpperBound)
+		}
+	})
+
+	t.Run("No hits", func(t *testing.T) {
+		stats := NewSearchCache().Stats()
+		if stats.BytesPerHit != 0 {
+			t.Errorf("expected 0 bytes per hit, got %f", stats.BytesPerHit)
+		}
+		if len(stats.ResultSizes) != len(resultSizeBounds)+1 {
+			t.Errorf("expected %d buckets, got %d", len(resultSizeBounds)+1, len(stats.ResultSizes))
+		}
+	})
+}
+
+func TestSearchCacheSharding(t *testing.T) {
+	t.Run("Small caches use a single shard", func(t *testing.T) {
+		for _, size := range []int{100, DefaultMaxCacheSize, 2047} {
+			if shards := len(NewSearchCacheWithSize(size).shards); shards != 1 {
+				t.Errorf("%d entries: expected 1 shard, got %d", size, shards)
+			}
+		}
+	})
+
+	t.Run("Capacity is split between shards", func(t *testing.T) {
+		cache := NewSearchCacheWithSize(5000)
+		if len(cache.shards) < 2 {
+			t.Fatalf("expected several shards, got %d", len(cache.shards))
+		}
+
+		total := 0
+		for _, s := range cache.shards {
+			total += s.maxSize
+		}
+		if total != 5000 {
+			t.Errorf("expected a total capacity of 5000, got %d", total)
+		}
+	})
+
+	t.Run("Size never exceeds the maximum", func(t *testing.T) {
+		cache := NewSearchCacheWithSize(1000)
+		testData := []CityData{{City: "Test"}}
+		for i := 0; i < 5000; i++ {
+			cache.Set(fmt.Sprintf("key%d", i), testData)
+		}
+
+		if cache.Size() > 1000 {
+			t.Errorf("cache size should be at most 1000, got %d", cache.Size())
+		}
+		if stats := cache.Stats(); stats.Evictions != uint64(5000-cache.Size()) {
+			t.Errorf("expected %d evictions, got %d", 5000-cache.Size(), stats.Evictions)
+		}
+	})
+
+	t.Run("Concurrent access", func(t *testing.T) {
+		cache := NewSearchCacheWithSize(256)
+		testData := []CityData{{City: "Test"}}
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					key := fmt.Sprintf("key%d", (g*31+i)%512)
+					if _, ok := cache.Get(key); !ok {
+						cache.Set(key, testData)
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		stats := cache.Stats()
+		if stats.Hits+stats.Misses != 8000 {
+			t.Errorf("expected 8000 lookups, got %d", stats.Hits+stats.Misses)
+		}
+		if cache.Size() > 256 {
+			t.Errorf("cache size should be at most 256, got %d", cache.Size())
+		}
+	})
+}
+
+// BenchmarkSearchCacheGetParallel measures concurrent hits. Run with
+// -cpu=1,2,4,8 to compare how a single shard and the maximum sharding scale.
+func BenchmarkSearchCacheGetParallel(b *testing.B) {
+	keys := make([]string, 512)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("city:key%d", i)
+	}
+
+	for _, bm := range []struct {
+		name  string
+		cache *SearchCache
+	}{
+		{"SingleShard", newSearchCache(DefaultMaxCacheSize, 1)},
+		{"Sharded", newSearchCache(DefaultMaxCacheSize, maxCacheShards)},
+	} {
+		for _, key := range keys {
+			bm.cache.Set(key, []CityData{{City: key}})
+		}
+
+		b.Run(bm.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					bm.cache.Get(keys[i%len(keys)])
+					i++
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkSearchCacheMixedParallel measures concurrent lookups with 10%
+// misses that store a new result and evict
+func BenchmarkSearchCacheMixedParallel(b *testing.B) {
+	keys := make([]string, 2*DefaultMaxCacheSize)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("city:key%d", i)
+	}
+	result := []CityData{{City: "Test"}}
+
+	for _, bm := range []struct {
+		name  string
+		cache *SearchCache
+	}{
+		{"SingleShard", newSearchCache(DefaultMaxCacheSize, 1)},
+		{"Sharded", newSearchCache(DefaultMaxCacheSize, maxCacheShards)},
+	} {
+		for _, key := range keys[:DefaultMaxCacheSize] {
+			bm.cache.Set(key, result)
+		}
+
+		b.Run(bm.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					key := keys[i%DefaultMaxCacheSize]
+					if i%10 == 0 {
+						key = keys[DefaultMaxCacheSize+i%DefaultMaxCacheSize]
+					}
+					if _, ok := bm.cache.Get(key); !ok {
+						bm.cache.Set(key, result)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
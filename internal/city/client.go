@@ -0,0 +1,594 @@
+package city
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Client runs city searches against its own dataset, with its own
+// normalization pipeline and result cache. The package-level search
+// functions use a default Client backed by the bundled dataset.
+type Client struct {
+	mu           sync.RWMutex
+	pipeline     []NormalizationStage
+	cache        *SearchCache
+	data         *dataset // Nil until the bundled dataset is loaded
+	origin       datasetOrigin
+	generation   uint64 // Incremented whenever cached results may go stale
+	provider     DataProvider
+	errorOnEmpty bool
+
+	errorOnNotFound   bool
+	errorOnEmptyInput bool
+
+	update updateState // Last applied update, guarded by mu
+
+	// providerCities holds the provider's cities for the scanning searches,
+	// valid while providerGeneration equals generation
+	providerCities     []CityData
+	providerGeneration uint64
+
+	hooks atomic.Pointer[hooksHolder] // Nil without hooks
+}
+
+// ClientOptions provides configuration for a Client
+type ClientOptions struct {
+	// Normalization is the ordered pipeline applied to queries and city names
+	// before they are compared. Nil uses the provider's normalization if it
+	// is a NameNormalizer, else DefaultNormalization; an empty, non-nil
+	// pipeline compares names as-is.
+	Normalization []NormalizationStage
+
+	// CacheSize is the maximum number of cached lookups, 0 uses DefaultMaxCacheSize
+	CacheSize int
+
+	// Cities is the client's dataset. Nil lazily loads the bundled dataset;
+	// an empty, non-nil slice starts the client empty so it can be populated
+	// later with AddCity, SetCities or Reload.
+	Cities []CityData
+
+	// ErrorOnEmptyDataset makes queries return ErrDatasetEmpty while the
+	// client has no cities, instead of empty results
+	ErrorOnEmptyDataset bool
+
+	// ErrorOnNotFound makes queries return ErrNotFound instead of empty results
+	ErrorOnNotFound bool
+
+	// ErrorOnEmptyInput makes queries with empty or blank input return a
+	// ValidationError instead of empty results
+	ErrorOnEmptyInput bool
+
+	// Hooks instruments the client's lookups, nil disables instrumentation
+	Hooks Hooks
+
+	// Provider serves the cities instead of the client's own dataset, e.g.
+	// from a database. Cities and ErrorOnEmptyDataset are then ignored, and
+	// the methods changing the dataset return ErrProviderManaged.
+	Provider DataProvider
+}
+
+// DefaultClientOptions returns the default client configuration
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		Normalization: DefaultNormalization(),
+		CacheSize:     DefaultMaxCacheSize,
+	}
+}
+
+// NewClient creates a new client with the given options
+func NewClient(options ClientOptions) *Client {
+	return newClient(options, NewSearchCacheWithSize(options.CacheSize))
+}
+
+func newClient(options ClientOptions, cache *SearchCache) *Client {
+	pipeline := options.Normalization
+	if normalizer, ok := options.Provider.(NameNormalizer); ok && pipeline == nil {
+		pipeline = normalizer.Normalization()
+	}
+	if pipeline == nil {
+		pipeline = DefaultNormalization()
+	}
+
+	client := &Client{
+		pipeline:     append([]NormalizationStage(nil), pipeline...),
+		cache:        cache,
+		errorOnEmpty: options.ErrorOnEmptyDataset,
+
+		errorOnNotFound:   options.ErrorOnNotFound,
+		errorOnEmptyInput: options.ErrorOnEmptyInput,
+		provider:          options.Provider,
+	}
+	if options.Cities != nil {
+		client.data = newDataset(options.Cities, client.pipeline, false)
+		client.origin.custom = true
+	}
+	client.SetHooks(options.Hooks)
+
+	return client
+}
+
+// defaultClient backs the package-level functions and shares the global cache
+var defaultClient = newClient(DefaultClientOptions(), searchCache)
+
+// DefaultClient returns the client used by the package-level functions
+func DefaultClient() *Client {
+	return defaultClient
+}
+
+// Normalization returns a copy of the client's normalization pipeline
+func (c *Client) Normalization() []NormalizationStage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]NormalizationStage(nil), c.pipeline...)
+}
+
+// SetNormalization replaces the client's normalization pipeline, rebuilds
+// the name index and clears the cache, since results depend on the pipeline
+func (c *Client) SetNormalization(stages ...NormalizationStage) {
+	c.editNormalization(func([]NormalizationStage) ([]NormalizationStage, bool) {
+		return append([]NormalizationStage(nil), stages...), true
+	})
+}
+
+// InsertNormalizationStage inserts a stage at the given position of the
+// pipeline. Positions outside the pipeline append the stage.
+func (c *Client) InsertNormalizationStage(index int, stage NormalizationStage) {
+	c.editNormalization(func(pipeline []NormalizationStage) ([]NormalizationStage, bool) {
+		if index < 0 || index > len(pipeline) {
+			index = len(pipeline)
+		}
+
+		edited := make([]NormalizationStage, 0, len(pipeline)+1)
+		edited = append(edited, pipeline[:index]...)
+		edited = append(edited, stage)
+		return append(edited, pipeline[index:]...), true
+	})
+}
+
+// RemoveNormalizationStage removes every stage with the given name and
+// reports whether any was removed
+func (c *Client) RemoveNormalizationStage(name string) bool {
+	return c.editNormalization(func(pipeline []NormalizationStage) ([]NormalizationStage, bool) {
+		kept := make([]NormalizationStage, 0, len(pipeline))
+		for _, stage := range pipeline {
+			if stage.Name != name {
+				kept = append(kept, stage)
+			}
+		}
+		return kept, len(kept) != len(pipeline)
+	})
+}
+
+// editNormalization replaces the pipeline with the result of edit under the
+// write lock, so concurrent edits cannot lose each other's changes. The name
+// index is rebuilt and the cache cleared once, and only if edit reports a
+// change. The pipeline passed to edit must not be modified, since searches
+// may still be using it.
+func (c *Client) editNormalization(edit func(pipeline []NormalizationStage) ([]NormalizationStage, bool)) bool {
+	c.mu.Lock()
+	pipeline, changed := edit(c.pipeline)
+	if !changed {
+		c.mu.Unlock()
+		return false
+	}
+
+	c.pipeline = pipeline
+	if c.data != nil {
+		c.data.reindexNames(c.pipeline)
+	}
+	c.generation++
+	c.mu.Unlock()
+
+	c.cache.Clear()
+	return true
+}
+
+// Normalize runs the input through the client's normalization pipeline
+func (c *Client) Normalize(input string) string {
+	return normalize(c.currentPipeline(), input)
+}
+
+// currentPipeline returns the pipeline for a single search. SetNormalization
+// replaces the slice rather than modifying it, so callers may keep using it
+// after the lock is released.
+func (c *Client) currentPipeline() []NormalizationStage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.pipeline
+}
+
+// snapshot returns the pipeline for a single search together with the
+// generation its results may be cached under
+func (c *Client) snapshot() ([]NormalizationStage, uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.pipeline, c.generation
+}
+
+// cacheResult caches the results of a search that started at generation,
+// unless the dataset or pipeline changed since. Changes increment the
+// generation under the write lock before clearing the cache, so stale
+// results are either skipped here or removed by the clear.
+func (c *Client) cacheResult(key string, results []CityData, generation uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.generation == generation {
+		c.cache.Set(key, results)
+	}
+}
+
+// view runs fn with read access to the client's dataset, loading the
+// bundled dataset first if needed
+func (c *Client) view(fn func(d *dataset, pipeline []NormalizationStage)) error {
+	if err := c.ensureLoaded(); err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.errorOnEmpty && len(c.data.cities) == 0 {
+		return ErrDatasetEmpty
+	}
+	fn(c.data, c.pipeline)
+	return nil
+}
+
+// Preload decodes and indexes the bundled dataset now rather than on the
+// client's first query, e.g. to keep that cost out of the first request.
+// It does nothing for clients with a provider.
+func (c *Client) Preload() error {
+	if c.provider != nil {
+		return nil
+	}
+	return c.ensureLoaded()
+}
+
+// ensureLoaded loads the bundled dataset into a client created without cities
+func (c *Client) ensureLoaded() error {
+	c.mu.RLock()
+	loaded := c.data != nil
+	c.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	cities, err := LoadCityData()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.data == nil {
+		c.data = newDataset(cities, c.pipeline, false)
+	}
+	return nil
+}
+
+// Cities returns all cities of the client's dataset or provider
+func (c *Client) Cities() ([]CityData, error) {
+	return c.source().AllCities(context.Background())
+}
+
+// Len returns the number of cities in the client's dataset or provider
+func (c *Client) Len() (int, error) {
+	if c.provider != nil {
+		cities, err := c.allCities(context.Background())
+		return len(cities), err
+	}
+
+	if err := c.ensureLoaded(); err != nil {
+		return 0, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.data.cities), nil
+}
+
+// AddCity adds a city to the client's dataset, updating its indexes
+// incrementally, and clears the cache. It returns ErrProviderManaged for
+// clients with a provider.
+func (c *Client) AddCity(city CityData) error {
+	return c.AddCities(city)
+}
+
+// AddCities adds cities to the client's dataset, updating its indexes
+// incrementally, and clears the cache. Cities must have a name. It returns
+// ErrProviderManaged for clients with a provider.
+func (c *Client) AddCities(cities ...CityData) error {
+	if c.provider != nil {
+		return ErrProviderManaged
+	}
+
+	for _, city := range cities {
+		if strings.TrimSpace(city.City) == "" {
+			return NewValidationError("city", "city name is required", nil)
+		}
+	}
+
+	if err := c.ensureLoaded(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	for _, city := range cities {
+		c.data.add(city, c.pipeline)
+	}
+	c.origin = datasetOrigin{custom: true}
+	c.update = updateState{}
+	c.generation++
+	c.mu.Unlock()
+
+	c.cache.Clear()
+	return nil
+}
+
+// SetCities replaces the client's dataset, rebuilds its indexes and clears
+// the cache. The slice is copied before the client appends to it. It returns
+// ErrProviderManaged for clients with a provider.
+func (c *Client) SetCities(cities []CityData) error {
+	if c.provider != nil {
+		return ErrProviderManaged
+	}
+
+	c.setCities(cities, datasetOrigin{custom: true})
+	return nil
+}
+
+// setCities replaces the client's dataset with cities from origin and
+// forgets the last update, so the next one applies the remote dataset again
+func (c *Client) setCities(cities []CityData, origin datasetOrigin) {
+	if cities == nil {
+		cities = []CityData{}
+	}
+
+	c.mu.Lock()
+	c.data = newDataset(cities, c.pipeline, false)
+	c.origin = origin
+	c.update = updateState{}
+	c.generation++
+	c.mu.Unlock()
+
+	c.cache.Clear()
+}
+
+// Reload replaces the client's dataset with a fresh read of the bundled
+// dataset, e.g. to populate a client created empty. It returns
+// ErrProviderManaged for clients with a provider.
+func (c *Client) Reload() error {
+	if c.provider != nil {
+		return ErrProviderManaged
+	}
+
+	cities, err := loadBundledCityData()
+	if err != nil {
+		return NewDataLoadError("reload", err)
+	}
+
+	c.setCities(cities, datasetOrigin{})
+	return nil
+}
+
+// emptyInputError returns the error for a query with empty input: nil, or a
+// ValidationError on clients created with ErrorOnEmptyInput
+func (c *Client) emptyInputError(field string) error {
+	if !c.errorOnEmptyInput {
+		return nil
+	}
+	return NewValidationError(field, "input is empty", nil)
+}
+
+// notFoundError returns the error for a query with the given number of
+// results: nil, or ErrNotFound on clients created with ErrorOnNotFound
+func (c *Client) notFoundError(query, operation string, results int) error {
+	if !c.errorOnNotFound || results > 0 {
+		return nil
+	}
+	return NewSearchError(query, operation, ErrNotFound)
+}
+
+// Cache returns the client's search cache
+func (c *Client) Cache() *SearchCache {
+	return c.cache
+}
+
+// Package-level functions using the default client
+
+// NearestCities returns up to limit cities ordered by their distance to the
+// coordinates, nearest first
+func NearestCities(lat, lng float64, limit int) ([]CityData, error) {
+	return defaultClient.NearestCities(lat, lng, limit)
+}
+
+// NearestCitiesContext is like NearestCities but passes ctx to the default
+// client's hooks and provider
+func NearestCitiesContext(ctx context.Context, lat, lng float64, limit int) ([]CityData, error) {
+	return defaultClient.NearestCitiesContext(ctx, lat, lng, limit)
+}
+
+// Disambiguate returns the cities named cityName, most populous first, with
+// display labels such as "Springfield, MO, US"
+func Disambiguate(cityName string) ([]Candidate, error) {
+	return defaultClient.Disambiguate(cityName)
+}
+
+// DisambiguateContext is like Disambiguate but passes ctx to the default
+// client's hooks and provider
+func DisambiguateContext(ctx context.Context, cityName string) ([]Candidate, error) {
+	return defaultClient.DisambiguateContext(ctx, cityName)
+}
+
+// CitiesInSameTimezone returns up to limit other cities in the timezone of the
+// named city, most populous first
+func CitiesInSameTimezone(cityName string, limit int) ([]CityData, error) {
+	return defaultClient.CitiesInSameTimezone(cityName, limit)
+}
+
+// CitiesInSameTimezoneContext is like CitiesInSameTimezone but passes ctx to
+// the default client's hooks and provider
+func CitiesInSameTimezoneContext(ctx context.Context, cityName string, limit int) ([]CityData, error) {
+	return defaultClient.CitiesInSameTimezoneContext(ctx, cityName, limit)
+}
+
+// FindFromTimezone returns the cities in an IANA timezone, most populous first
+func FindFromTimezone(timezone string) ([]CityData, error) {
+	return defaultClient.FindFromTimezone(timezone)
+}
+
+// FindFromTimezoneContext is like FindFromTimezone but passes ctx to the
+// default client's hooks and provider
+func FindFromTimezoneContext(ctx context.Context, timezone string) ([]CityData, error) {
+	return defaultClient.FindFromTimezoneContext(ctx, timezone)
+}
+
+// LookupViaCity searches for cities by exact city name match, including
+// alternate and localized names such as "München" for Munich
+func LookupViaCity(cityName string) ([]CityData, error) {
+	return defaultClient.LookupViaCity(cityName)
+}
+
+// LookupViaCityContext is like LookupViaCity but passes ctx to the default
+// client's hooks and provider
+func LookupViaCityContext(ctx context.Context, cityName string) ([]CityData, error) {
+	return defaultClient.LookupViaCityContext(ctx, cityName)
+}
+
+// FindFromCityStateProvince searches for cities using partial matching
+// across city, state, province, and country fields, sorted by relevance
+func FindFromCityStateProvince(searchString string) ([]CityData, error) {
+	return defaultClient.FindFromCityStateProvince(searchString)
+}
+
+// FindFromCityStateProvinceContext is like FindFromCityStateProvince but passes
+// ctx to the default client's hooks and provider
+func FindFromCityStateProvinceContext(ctx context.Context, searchString string) ([]CityData, error) {
+	return defaultClient.FindFromCityStateProvinceContext(ctx, searchString)
+}
+
+// FindFromCityStateProvinceScored works like FindFromCityStateProvince and
+// also returns the relevance score of each result
+func FindFromCityStateProvinceScored(searchString string) ([]ScoredCity, error) {
+	return defaultClient.FindFromCityStateProvinceScored(searchString)
+}
+
+// FindFromCityStateProvinceScoredContext is like
+// FindFromCityStateProvinceScored but passes ctx to the default client's hooks
+// and provider
+func FindFromCityStateProvinceScoredContext(ctx context.Context, searchString string) ([]ScoredCity, error) {
+	return defaultClient.FindFromCityStateProvinceScoredContext(ctx, searchString)
+}
+
+// FindFromIsoCode searches for cities by ISO2 or ISO3 country codes
+func FindFromIsoCode(isoCode string) ([]CityData, error) {
+	return defaultClient.FindFromIsoCode(isoCode)
+}
+
+// FindFromIsoCodeContext is like FindFromIsoCode but passes ctx to the default
+// client's hooks and provider
+func FindFromIsoCodeContext(ctx context.Context, isoCode string) ([]CityData, error) {
+	return defaultClient.FindFromIsoCodeContext(ctx, isoCode)
+}
+
+// SearchCities provides a flexible search function with options
+func SearchCities(query string, options SearchOptions) ([]CityData, error) {
+	return defaultClient.SearchCities(query, options)
+}
+
+// SearchCitiesContext is like SearchCities but passes ctx to the default
+// client's hooks and provider
+func SearchCitiesContext(ctx context.Context, query string, options SearchOptions) ([]CityData, error) {
+	return defaultClient.SearchCitiesContext(ctx, query, options)
+}
+
+// LookupViaAirportCode returns the city served by the airport with the given
+// IATA or ICAO code
+func LookupViaAirportCode(code string) ([]CityData, error) {
+	return defaultClient.LookupViaAirportCode(code)
+}
+
+// LookupViaAirportCodeContext is like LookupViaAirportCode but passes ctx to
+// the default client's hooks and provider
+func LookupViaAirportCodeContext(ctx context.Context, code string) ([]CityData, error) {
+	return defaultClient.LookupViaAirportCodeContext(ctx, code)
+}
+
+// FindCities searches for cities matching every non-empty field of the query
+func FindCities(query CityQuery) ([]CityData, error) {
+	return defaultClient.FindCities(query)
+}
+
+// FindCitiesContext is like FindCities but passes ctx to the default client's
+// hooks and provider
+func FindCitiesContext(ctx context.Context, query CityQuery) ([]CityData, error) {
+	return defaultClient.FindCitiesContext(ctx, query)
+}
+
+// ListProvinces returns the sorted, distinct province names of a country
+// identified by its ISO2 or ISO3 code
+func ListProvinces(isoCode string) ([]string, error) {
+	return defaultClient.ListProvinces(isoCode)
+}
+
+// ListProvincesContext is like ListProvinces but passes ctx to the default
+// client's hooks and provider
+func ListProvincesContext(ctx context.Context, isoCode string) ([]string, error) {
+	return defaultClient.ListProvincesContext(ctx, isoCode)
+}
+
+// DistanceBetweenNames returns the distance in kilometers between two named
+// cities, resolving ambiguous names to the most populous match
+func DistanceBetweenNames(a, b string) (float64, error) {
+	return defaultClient.DistanceBetweenNames(a, b)
+}
+
+// DSTInfo returns the DST details of the named city's timezone for the year
+func DSTInfo(cityName string, year int) (DSTDetails, error) {
+	return defaultClient.DSTInfo(cityName, year)
+}
+
+// CheckTimezoneConsistency checks the default client's dataset for cities
+// whose timezone is implausible for their coordinates
+func CheckTimezoneConsistency(maxDeviation time.Duration) ([]TimezoneInconsistency, error) {
+	return defaultClient.CheckTimezoneConsistency(maxDeviation)
+}
+
+// ValidateTimezones checks the timezone names of the default client's dataset
+// against the available tz database
+func ValidateTimezones() ([]TimezoneIssue, error) {
+	return defaultClient.ValidateTimezones()
+}
+
+// Preload decodes and indexes the bundled dataset of the default client
+func Preload() error {
+	return defaultClient.Preload()
+}
+
+// UpdateDatasetFromURL updates the default client's dataset from url
+func UpdateDatasetFromURL(ctx context.Context, url string, options UpdateOptions) (DatasetUpdate, error) {
+	return defaultClient.UpdateFromURL(ctx, url, options)
+}
+
+// Watch keeps the default client's dataset updated from url until ctx is cancelled
+func Watch(ctx context.Context, url string, options WatchOptions) error {
+	return defaultClient.Watch(ctx, url, options)
+}
+
+// SetHooks sets the hooks instrumenting the default client's lookups
+func SetHooks(hooks Hooks) {
+	defaultClient.SetHooks(hooks)
+}
+
+// SetNormalization replaces the default client's normalization pipeline
+func SetNormalization(stages ...NormalizationStage) {
+	defaultClient.SetNormalization(stages...)
+}
+
+// Normalization returns a copy of the default client's normalization pipeline
+func Normalization() []NormalizationStage {
+	return defaultClient.Normalization()
+}
@@ -0,0 +1,168 @@
+package city
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Run("Default options", func(t *testing.T) {
+		client := NewClient(DefaultClientOptions())
+
+		cities, err := client.LookupViaCity("chicago")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) == 0 {
+			t.Error("Should find Chicago")
+		}
+		if client.Cache() == DefaultClient().Cache() {
+			t.Error("New clients should not share the default cache")
+		}
+	})
+
+	t.Run("Nil pipeline uses default normalization", func(t *testing.T) {
+		client := NewClient(ClientOptions{})
+
+		if got := client.Normalize("Chicago"); got != "chicago" {
+			t.Errorf("Expected chicago, got %q", got)
+		}
+	})
+
+	t.Run("Empty pipeline compares names as-is", func(t *testing.T) {
+		client := NewClient(ClientOptions{Normalization: []NormalizationStage{}})
+
+		cities, err := client.LookupViaCity("chicago")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 0 {
+			t.Errorf("Expected no case-insensitive match, got %d", len(cities))
+		}
+	})
+}
+
+func TestClientNormalization(t *testing.T) {
+	t.Run("Folding diacritics matches ASCII spellings", func(t *testing.T) {
+		client := NewClient(DefaultClientOptions())
+
+		cities, err := client.LookupViaCity("Krakow")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		before := len(cities)
+
+		client.InsertNormalizationStage(1, FoldDiacriticsStage())
+		cities, err = client.LookupViaCity("Krakow")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) <= before {
+			t.Errorf("Expected more matches with folding, got %d (was %d)", len(cities), before)
+		}
+	})
+
+	t.Run("Synonyms and transliteration", func(t *testing.T) {
+		client := NewClient(ClientOptions{Normalization: []NormalizationStage{
+			LowercaseStage(),
+			StripPunctuationStage(),
+			ExpandSynonymsStage(CommonSynonyms),
+			TransliterateStage(CyrillicTransliteration),
+		}})
+
+		cities, err := client.FindCities(CityQuery{City: "Saint Petersburg", ISO2: "RU"})
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 {
+			t.Errorf("Expected St. Petersburg, got %d results", len(cities))
+		}
+
+		cities, err = client.LookupViaCity("Харків")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].City != "Kharkiv" {
+			t.Errorf("Expected Kharkiv, got %v", cities)
+		}
+	})
+
+	t.Run("Insert and remove stages", func(t *testing.T) {
+		client := NewClient(DefaultClientOptions())
+
+		client.InsertNormalizationStage(0, StripPunctuationStage())
+		client.InsertNormalizationStage(99, FoldDiacriticsStage())
+
+		pipeline := client.Normalization()
+		names := []string{StageStripPunctuation, StageLowercase, StageFoldDiacritics}
+		if len(pipeline) != len(names) {
+			t.Fatalf("Expected %d stages, got %d", len(names), len(pipeline))
+		}
+		for i, name := range names {
+			if pipeline[i].Name != name {
+				t.Errorf("Stage %d: expected %s, got %s", i, name, pipeline[i].Name)
+			}
+		}
+
+		if !client.RemoveNormalizationStage(StageLowercase) {
+			t.Error("Should remove lowercase stage")
+		}
+		if client.RemoveNormalizationStage(StageLowercase) {
+			t.Error("Should not remove a missing stage")
+		}
+		if got := len(client.Normalization()); got != 2 {
+			t.Errorf("Expected 2 stages, got %d", got)
+		}
+	})
+
+	t.Run("Concurrent stage edits", func(t *testing.T) {
+		client := NewClient(ClientOptions{Normalization: []NormalizationStage{}, Cities: []CityData{}})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				client.InsertNormalizationStage(0, NormalizationStage{
+					Name:  fmt.Sprintf("stage-%d", i),
+					Apply: strings.TrimSpace,
+				})
+			}(i)
+		}
+		wg.Wait()
+
+		if got := len(client.Normalization()); got != 20 {
+			t.Errorf("Expected 20 stages, got %d", got)
+		}
+	})
+
+	t.Run("Changing the pipeline clears the cache", func(t *testing.T) {
+		client := NewClient(DefaultClientOptions())
+		if _, err := client.LookupViaCity("Chicago"); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if client.Cache().Size() == 0 {
+			t.Fatal("Lookup should be cached")
+		}
+
+		client.SetNormalization(DefaultNormalization()...)
+		if client.Cache().Size() != 0 {
+			t.Errorf("Cache should be cleared, got %d entries", client.Cache().Size())
+		}
+	})
+
+	t.Run("Case-sensitive search skips normalization", func(t *testing.T) {
+		client := NewClient(DefaultClientOptions())
+		options := SearchOptions{CaseSensitive: true, ExactMatch: true}
+
+		cities, err := client.SearchCities("chicago", options)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 0 {
+			t.Errorf("Expected no matches, got %d", len(cities))
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package city
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ClientConfig is a JSON-serializable snapshot of a client's configuration,
+// e.g. to attach to a bug report and recreate the client with
+// NewClientFromConfig
+type ClientConfig struct {
+	Normalization       []StageConfig `json:"normalization"`
+	CacheSize           int           `json:"cache_size"`
+	ErrorOnEmptyDataset bool          `json:"error_on_empty_dataset"`
+	ErrorOnNotFound     bool          `json:"error_on_not_found"`
+	ErrorOnEmptyInput   bool          `json:"error_on_empty_input"`
+
+	// Dataset identifies the client's dataset, as returned by DatasetInfo
+	Dataset DatasetMetadata `json:"dataset"`
+
+	// Provider is the Go type of the client's DataProvider, if it has one
+	Provider string `json:"provider,omitempty"`
+}
+
+// StageConfig describes a normalization stage by its name and, for the
+// configurable built-in stages, its table. Custom stages are recorded by
+// name only and cannot be restored, even if they reuse a built-in name.
+type StageConfig struct {
+	Name   string            `json:"name"`
+	Config map[string]string `json:"config,omitempty"`
+	Custom bool              `json:"custom,omitempty"`
+}
+
+// ConfigSnapshot returns the client's configuration: its normalization
+// stages, cache size, error options and the identity of its dataset. Hooks
+// are not part of the snapshot.
+func (c *Client) ConfigSnapshot() (ClientConfig, error) {
+	info, err := c.DatasetInfo()
+	if err != nil {
+		return ClientConfig{}, err
+	}
+
+	config := ClientConfig{
+		Normalization:       []StageConfig{},
+		CacheSize:           c.cache.MaxSize(),
+		ErrorOnEmptyDataset: c.errorOnEmpty,
+		ErrorOnNotFound:     c.errorOnNotFound,
+		ErrorOnEmptyInput:   c.errorOnEmptyInput,
+		Dataset:             info,
+	}
+	if c.provider != nil {
+		config.Provider = fmt.Sprintf("%T", c.provider)
+	}
+
+	for _, stage := range c.Normalization() {
+		stageConfig := StageConfig{Name: stage.Name, Custom: !stage.builtin}
+		if len(stage.Config) > 0 {
+			stageConfig.Config = make(map[string]string, len(stage.Config))
+			for key, value := range stage.Config {
+				stageConfig.Config[key] = value
+			}
+		}
+		config.Normalization = append(config.Normalization, stageConfig)
+	}
+
+	return config, nil
+}
+
+// Options returns the client options of the configuration. Only built-in
+// normalization stages can be restored; the dataset is left to the caller.
+func (config ClientConfig) Options() (ClientOptions, error) {
+	pipeline := make([]NormalizationStage, 0, len(config.Normalization))
+	for _, stageConfig := range config.Normalization {
+		stage, err := stageConfig.stage()
+		if err != nil {
+			return ClientOptions{}, err
+		}
+		pipeline = append(pipeline, stage)
+	}
+
+	return ClientOptions{
+		Normalization:       pipeline,
+		CacheSize:           config.CacheSize,
+		ErrorOnEmptyDataset: config.ErrorOnEmptyDataset,
+		ErrorOnNotFound:     config.ErrorOnNotFound,
+		ErrorOnEmptyInput:   config.ErrorOnEmptyInput,
+	}, nil
+}
+
+// stage builds the built-in normalization stage the configuration describes
+func (sc StageConfig) stage() (NormalizationStage, error) {
+	if sc.Custom {
+		return NormalizationStage{}, NewValidationError("normalization", "custom normalization stages cannot be restored", sc.Name)
+	}
+
+	switch sc.Name {
+	case StageLowercase:
+		return LowercaseStage(), nil
+	case StageFoldDiacritics:
+		return FoldDiacriticsStage(), nil
+	case StageStripPunctuation:
+		return StripPunctuationStage(), nil
+	case StageExpandSynonyms:
+		return ExpandSynonymsStage(sc.Config), nil
+	case StageTransliterate:
+		table := make(map[rune]string, len(sc.Config))
+		for key, replacement := range sc.Config {
+			r, size := utf8.DecodeRuneInString(key)
+			if r == utf8.RuneError || size != len(key) {
+				return NormalizationStage{}, NewValidationError("normalization", "transliteration keys must be single runes", key)
+			}
+			table[r] = replacement
+		}
+		return TransliterateStage(table), nil
+	default:
+		return NormalizationStage{}, NewValidationError("normalization", "unknown normalization stage", sc.Name)
+	}
+}
+
+// NewClientFromConfig creates a client from a configuration snapshot. The
+// snapshot must describe the dataset embedded in this binary; for other
+// datasets and providers, use config.Options() with NewClient and set
+// ClientOptions.Cities or ClientOptions.Provider.
+func NewClientFromConfig(config ClientConfig) (*Client, error) {
+	options, err := config.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	embedded, err := embeddedDatasetInfo()
+	if err != nil {
+		return nil, err
+	}
+	if config.Provider != "" || config.Dataset.Version == "" {
+		return nil, NewValidationError("dataset", "the snapshot does not describe the embedded dataset", config.Dataset.Cities)
+	}
+	if config.Dataset.Version != embedded.Version || config.Dataset.Lite != embedded.Lite {
+		return nil, NewValidationError("dataset", fmt.Sprintf("the snapshot's dataset %s differs from the embedded dataset %s", config.Dataset.Version, embedded.Version), config.Dataset.Version)
+	}
+
+	return NewClient(options), nil
+}
@@ -0,0 +1,128 @@
+package city
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigSnapshot(t *testing.T) {
+	t.Run("JSON round trip", func(t *testing.T) {
+		client := NewClient(ClientOptions{
+			Normalization: []NormalizationStage{
+				LowercaseStage(),
+				FoldDiacriticsStage(),
+				StripPunctuationStage(),
+				ExpandSynonymsStage(CommonSynonyms),
+				TransliterateStage(CyrillicTransliteration),
+			},
+			CacheSize:         42,
+			ErrorOnNotFound:   true,
+			ErrorOnEmptyInput: true,
+		})
+
+		snapshot, err := client.ConfigSnapshot()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		raw, err := json.Marshal(snapshot)
+		if err != nil {
+			t.Fatalf("Should marshal: %v", err)
+		}
+
+		var decoded ClientConfig
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			t.Fatalf("Should unmarshal: %v", err)
+		}
+		restored, err := NewClientFromConfig(decoded)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		restoredSnapshot, err := restored.ConfigSnapshot()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if !reflect.DeepEqual(snapshot, restoredSnapshot) {
+			t.Errorf("Expected the restored snapshot to match\n got: %+v\nwant: %+v", restoredSnapshot, snapshot)
+		}
+
+		// The restored client behaves like the original
+		for _, query := range []string{"St. Louis", "киев", "Zürich"} {
+			want, _ := client.LookupViaCity(query)
+			got, _ := restored.LookupViaCity(query)
+			if len(want) == 0 || len(got) != len(want) {
+				t.Errorf("%s: expected %d results, got %d", query, len(want), len(got))
+			}
+		}
+		if _, err := restored.LookupViaCity("NonExistentCity"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+		if restored.Cache().MaxSize() != 42 {
+			t.Errorf("Expected cache size 42, got %d", restored.Cache().MaxSize())
+		}
+	})
+
+	t.Run("Dataset identity", func(t *testing.T) {
+		embedded, _ := DatasetInfo()
+		snapshot, err := NewClient(DefaultClientOptions()).ConfigSnapshot()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if snapshot.Dataset.Version != embedded.Version {
+			t.Errorf("Expected the embedded dataset version, got %+v", snapshot.Dataset)
+		}
+
+		custom, err := NewClient(ClientOptions{Cities: []CityData{{City: "Springfield"}}}).ConfigSnapshot()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if custom.Dataset.Cities != 1 {
+			t.Errorf("Expected 1 city, got %+v", custom.Dataset)
+		}
+		if _, err := NewClientFromConfig(custom); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected a custom dataset to be rejected, got %v", err)
+		}
+
+		snapshot.Dataset.Version = "other"
+		if _, err := NewClientFromConfig(snapshot); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected another dataset version to be rejected, got %v", err)
+		}
+
+		provided, _ := NewClient(ClientOptions{Provider: NewDatasetProvider(providerCities)}).ConfigSnapshot()
+		if provided.Provider == "" {
+			t.Error("Expected the provider type to be recorded")
+		}
+	})
+
+	t.Run("Custom stages cannot be restored", func(t *testing.T) {
+		client := NewClient(ClientOptions{Normalization: []NormalizationStage{
+			{Name: "custom", Apply: func(s string) string { return s }},
+		}})
+		snapshot, err := client.ConfigSnapshot()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if _, err := snapshot.Options(); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("Custom stages with built-in names are not restored", func(t *testing.T) {
+		client := NewClient(ClientOptions{Normalization: []NormalizationStage{
+			{Name: StageLowercase, Apply: strings.ToUpper},
+		}})
+		snapshot, err := client.ConfigSnapshot()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(snapshot.Normalization) != 1 || !snapshot.Normalization[0].Custom {
+			t.Errorf("Expected the stage recorded as custom, got %+v", snapshot.Normalization)
+		}
+		if _, err := snapshot.Options(); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package city
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultMaxTimezoneDeviation is the default tolerance between a city's
+// standard timezone offset and the solar time at its longitude. Political
+// zones routinely stray a couple of hours from solar time (western China,
+// Spain, Argentina), so only larger gaps point at data-entry errors.
+const DefaultMaxTimezoneDeviation = 3 * time.Hour
+
+// TimezoneInconsistency describes a city whose stored timezone is implausible
+// for its coordinates
+type TimezoneInconsistency struct {
+	City        CityData
+	SolarOffset time.Duration // Mean solar time offset at the city's longitude
+	ZoneOffset  time.Duration // Standard UTC offset of the city's timezone
+	Deviation   time.Duration // Absolute difference, wrapped around the date line
+	Err         error         // Set when the timezone could not be loaded
+}
+
+// CheckTimezoneConsistency checks the client's dataset for cities whose
+// timezone offset deviates from solar time by more than maxDeviation.
+// A non-positive maxDeviation uses DefaultMaxTimezoneDeviation.
+func (c *Client) CheckTimezoneConsistency(maxDeviation time.Duration) ([]TimezoneInconsistency, error) {
+	cities, err := c.Cities()
+	if err != nil {
+		return nil, err
+	}
+	return FindTimezoneInconsistencies(cities, maxDeviation), nil
+}
+
+// FindTimezoneInconsistencies returns the cities whose timezone offset
+// deviates from solar time by more than maxDeviation, and the cities whose
+// timezone cannot be loaded. A non-positive maxDeviation uses
+// DefaultMaxTimezoneDeviation.
+func FindTimezoneInconsistencies(cities []CityData, maxDeviation time.Duration) []TimezoneInconsistency {
+	checker := newTimezoneChecker(maxDeviation)
+
+	var inconsistencies []TimezoneInconsistency
+	for _, city := range cities {
+		if inconsistency, ok := checker.check(city); !ok {
+			inconsistencies = append(inconsistencies, inconsistency)
+		}
+	}
+
+	return inconsistencies
+}
+
+// flagTimezoneWarnings sets TimezoneWarning on each city whose timezone is
+// implausible for its coordinates
+func flagTimezoneWarnings(cities []CityData) {
+	checker := newTimezoneChecker(DefaultMaxTimezoneDeviation)
+	for i := range cities {
+		_, ok := checker.check(cities[i])
+		cities[i].TimezoneWarning = !ok
+	}
+}
+
+// timezoneChecker compares cities against their zones, loading each zone once
+type timezoneChecker struct {
+	maxDeviation time.Duration
+	reference    time.Time
+	offsets      map[string]time.Duration
+	errors       map[string]error
+}
+
+func newTimezoneChecker(maxDeviation time.Duration) *timezoneChecker {
+	if maxDeviation <= 0 {
+		maxDeviation = DefaultMaxTimezoneDeviation
+	}
+	return &timezoneChecker{
+		maxDeviation: maxDeviation,
+		reference:    time.Now(),
+		offsets:      make(map[string]time.Duration),
+		errors:       make(map[string]error),
+	}
+}
+
+// check reports whether the city's timezone is plausible for its coordinates
+func (tc *timezoneChecker) check(city CityData) (TimezoneInconsistency, bool) {
+	result := TimezoneInconsistency{
+		City:        city,
+		SolarOffset: solarOffset(city.Lng),
+	}
+
+	zoneOffset, err := tc.standardOffset(city.Timezone)
+	if err != nil {
+		result.Err = err
+		return result, false
+	}
+
+	result.ZoneOffset = zoneOffset
+	result.Deviation = offsetDeviation(result.SolarOffset, zoneOffset)
+	return result, result.Deviation <= tc.maxDeviation
+}
+
+// standardOffset returns the standard (non-DST) UTC offset of a zone, taken as
+// the smaller of its January and July offsets so both hemispheres work
+func (tc *timezoneChecker) standardOffset(timezone string) (time.Duration, error) {
+	if offset, ok := tc.offsets[timezone]; ok {
+		return offset, nil
+	}
+	if err, ok := tc.errors[timezone]; ok {
+		return 0, err
+	}
+
+	if timezone == "" {
+		err := NewValidationError("timezone", "timezone is empty", nil)
+		tc.errors[timezone] = err
+		return 0, err
+	}
+
+	location, err := LoadLocation(timezone)
+	if err != nil {
+		tc.errors[timezone] = err
+		return 0, err
+	}
+
+	year := tc.reference.Year()
+	_, january := time.Date(year, time.January, 1, 12, 0, 0, 0, location).Zone()
+	_, july := time.Date(year, time.July, 1, 12, 0, 0, 0, location).Zone()
+
+	offset := time.Duration(min(january, july)) * time.Second
+	tc.offsets[timezone] = offset
+	return offset, nil
+}
+
+// solarOffset returns the mean solar time offset from UTC at a longitude
+func solarOffset(lng float64) time.Duration {
+	return time.Duration(lng / 15 * float64(time.Hour))
+}
+
+// offsetDeviation returns the absolute difference between two UTC offsets,
+// wrapped to at most 12 hours so zones across the date line compare correctly
+func offsetDeviation(a, b time.Duration) time.Duration {
+	day := 24 * time.Hour
+	diff := time.Duration(math.Abs(float64(a - b)))
+	diff %= day
+	if diff > day/2 {
+		diff = day - diff
+	}
+	return diff
+}
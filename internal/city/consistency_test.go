@@ -0,0 +1,117 @@
+package city
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFindTimezoneInconsistencies(t *testing.T) {
+	t.Run("Plausible timezone passes", func(t *testing.T) {
+		cities := []CityData{{City: "Chicago", Lng: -87.75005497, Timezone: "America/Chicago"}}
+
+		if got := FindTimezoneInconsistencies(cities, 0); len(got) != 0 {
+			t.Errorf("Expected no inconsistencies, got %v", got)
+		}
+	})
+
+	t.Run("US city with Asian zone is flagged", func(t *testing.T) {
+		cities := []CityData{{City: "Chicago", Lng: -87.75005497, Timezone: "Asia/Tokyo"}}
+
+		got := FindTimezoneInconsistencies(cities, 0)
+		if len(got) != 1 {
+			t.Fatalf("Expected 1 inconsistency, got %d", len(got))
+		}
+		if got[0].ZoneOffset != 9*time.Hour {
+			t.Errorf("Expected zone offset 9h, got %v", got[0].ZoneOffset)
+		}
+		if got[0].Deviation <= DefaultMaxTimezoneDeviation {
+			t.Errorf("Expected deviation above default, got %v", got[0].Deviation)
+		}
+	})
+
+	t.Run("Zones across the date line compare correctly", func(t *testing.T) {
+		cities := []CityData{{City: "Kiritimati", Lng: -157.4, Timezone: "Pacific/Kiritimati"}}
+
+		if got := FindTimezoneInconsistencies(cities, 0); len(got) != 0 {
+			t.Errorf("Expected no inconsistencies, got %v", got[0].Deviation)
+		}
+	})
+
+	t.Run("Unknown and empty zones are reported with errors", func(t *testing.T) {
+		cities := []CityData{
+			{City: "Nowhere", Timezone: "Invalid/Zone"},
+			{City: "Station", Timezone: ""},
+		}
+
+		got := FindTimezoneInconsistencies(cities, 0)
+		if len(got) != 2 {
+			t.Fatalf("Expected 2 inconsistencies, got %d", len(got))
+		}
+		for _, inconsistency := range got {
+			if inconsistency.Err == nil {
+				t.Errorf("Expected error for %s", inconsistency.City.City)
+			}
+		}
+
+		var validationErr ValidationError
+		if !errors.As(got[1].Err, &validationErr) {
+			t.Errorf("Expected ValidationError for empty zone, got %T", got[1].Err)
+		}
+	})
+
+	t.Run("Custom tolerance", func(t *testing.T) {
+		cities := []CityData{{City: "Madrid", Lng: -3.68, Timezone: "Europe/Madrid"}}
+
+		if got := FindTimezoneInconsistencies(cities, 30*time.Minute); len(got) != 1 {
+			t.Errorf("Expected Madrid flagged with 30m tolerance, got %d", len(got))
+		}
+	})
+}
+
+func TestOffsetDeviation(t *testing.T) {
+	tests := []struct {
+		a, b     time.Duration
+		expected time.Duration
+	}{
+		{time.Hour, 3 * time.Hour, 2 * time.Hour},
+		{-10 * time.Hour, 14 * time.Hour, 0},
+		{11 * time.Hour, -11 * time.Hour, 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := offsetDeviation(tt.a, tt.b); got != tt.expected {
+			t.Errorf("offsetDeviation(%v, %v) = %v, expected %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestCheckTimezoneConsistency(t *testing.T) {
+	t.Run("Dataset check", func(t *testing.T) {
+		inconsistencies, err := CheckTimezoneConsistency(0)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		cities, _ := LoadCityData()
+		if len(inconsistencies) > len(cities)/100 {
+			t.Errorf("Expected only a handful of inconsistencies, got %d", len(inconsistencies))
+		}
+	})
+
+	t.Run("Search results are flagged on request", func(t *testing.T) {
+		options := DefaultSearchOptions()
+		options.ExactMatch = true
+		options.FlagTimezoneWarnings = true
+
+		cities, err := SearchCities("chicago", options)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		for _, city := range cities {
+			if city.TimezoneWarning {
+				t.Errorf("Did not expect warning for %s (%s)", city.City, city.Timezone)
+			}
+		}
+	})
+}
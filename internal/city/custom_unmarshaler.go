@@ -1,8 +1,10 @@
 package city
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
 )
 
@@ -21,6 +23,10 @@ type CityDataRaw struct {
 	CityASCII     string      `json:"city_ascii"`
 	StateANSI     string      `json:"state_ansi"`
 	ExactProvince string      `json:"exactProvince"`
+
+	AlternateNames  []AlternateName `json:"alt_names"`
+	AreaKm2         float64         `json:"area_km2"`
+	TimezoneWarning bool            `json:"timezone_warning"`
 }
 
 // ToCityData converts the raw structure to the final CityData structure
@@ -39,6 +45,10 @@ func (raw *CityDataRaw) ToCityData() CityData {
 		CityASCII:     raw.CityASCII,
 		StateANSI:     raw.StateANSI,
 		ExactProvince: raw.ExactProvince,
+
+		AlternateNames:  raw.AlternateNames,
+		AreaKm2:         raw.AreaKm2,
+		TimezoneWarning: raw.TimezoneWarning,
 	}
 }
 
@@ -85,17 +95,50 @@ func convertToString(value interface{}) string {
 	}
 }
 
-// UnmarshalCityData unmarshals JSON data into CityData slice with flexible type handling
+// UnmarshalCityData unmarshals JSON data into CityData slice with flexible type handling.
+// Strings repeated across records, such as countries, provinces and
+// timezones, are interned so the cities share one copy of each.
 func UnmarshalCityData(data []byte) ([]CityData, error) {
-	var rawData []CityDataRaw
-	if err := json.Unmarshal(data, &rawData); err != nil {
+	return decodeCityData(bytes.NewReader(data), newStringInterner())
+}
+
+// decodeCityData decodes a JSON array of cities one record at a time, so only
+// the converted cities are held rather than every raw record as well.
+// A nil interner keeps each record's strings separate.
+func decodeCityData(r io.Reader, in *stringInterner) ([]CityData, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal raw city data: %w", err)
 	}
+	if tok == nil {
+		return nil, nil // JSON null
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("failed to unmarshal raw city data: expected array, got %v", tok)
+	}
 
-	cities := make([]CityData, len(rawData))
-	for i, raw := range rawData {
-		cities[i] = raw.ToCityData()
+	cities := []CityData{}
+	for dec.More() {
+		var raw CityDataRaw
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal raw city data: %w", err)
+		}
+
+		city := raw.ToCityData()
+		in.internCity(&city)
+		cities = append(cities, city)
+	}
+
+	// Consume the closing bracket and reject trailing data
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal raw city data: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("failed to unmarshal raw city data: unexpected data after array")
 	}
 
-	return cities, nil
+	// Release the spare capacity left by append
+	return append(make([]CityData, 0, len(cities)), cities...), nil
 }
@@ -1,7 +1,14 @@
 package city
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"runtime"
 	"testing"
+	"unsafe"
+
+	"github.com/richoandika/city-timezones-go/data"
 )
 
 func TestUnmarshalCityData(t *testing.T) {
@@ -324,3 +331,80 @@ func TestConvertToString(t *testing.T) {
 		}
 	})
 }
+
+func TestUnmarshalCityDataInterning(t *testing.T) {
+	jsonData := `[
+		{"city": "Springfield", "iso2": "US", "country": "United States of America", "timezone": "America/Chicago", "province": "Illinois"},
+		{"city": "Springfield", "iso2": "US", "country": "United States of America", "timezone": "America/Chicago", "province": "Missouri"}
+	]`
+
+	cities, err := UnmarshalCityData([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("Should not have error: %v", err)
+	}
+	if len(cities) != 2 || cap(cities) != 2 {
+		t.Fatalf("Expected 2 cities without spare capacity, got len %d cap %d", len(cities), cap(cities))
+	}
+
+	if unsafe.StringData(cities[0].Timezone) != unsafe.StringData(cities[1].Timezone) {
+		t.Error("Expected equal timezones to share one string")
+	}
+	if cities[0].Province == cities[1].Province {
+		t.Error("Different provinces should be kept")
+	}
+
+	t.Run("Trailing data", func(t *testing.T) {
+		if _, err := UnmarshalCityData([]byte(`[] []`)); err == nil {
+			t.Error("Should have error for data after the array")
+		}
+	})
+
+	t.Run("Not an array", func(t *testing.T) {
+		if _, err := UnmarshalCityData([]byte(`{"city": "Chicago"}`)); err == nil {
+			t.Error("Should have error for an object")
+		}
+	})
+}
+
+// BenchmarkDecodeCityData compares the heap retained by the decoded bundled
+// dataset with and without string interning (retained-B/op)
+func BenchmarkDecodeCityData(b *testing.B) {
+	zr, err := gzip.NewReader(bytes.NewReader(data.CityMap))
+	if err != nil {
+		b.Fatal(err)
+	}
+	cityMap, err := io.ReadAll(zr)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Plain", func(b *testing.B) {
+		benchmarkDecodeCityData(b, cityMap, func() *stringInterner { return nil })
+	})
+	b.Run("Interned", func(b *testing.B) {
+		benchmarkDecodeCityData(b, cityMap, newStringInterner)
+	})
+}
+
+func benchmarkDecodeCityData(b *testing.B, cityMap []byte, interner func() *stringInterner) {
+	b.ReportAllocs()
+
+	var retained int64
+	var before, after runtime.MemStats
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		cities, err := decodeCityData(bytes.NewReader(cityMap), interner())
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		retained += int64(after.HeapAlloc) - int64(before.HeapAlloc)
+		runtime.KeepAlive(cities)
+	}
+
+	b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+}
@@ -0,0 +1,126 @@
+package city
+
+import (
+	"sort"
+	"strings"
+)
+
+// dataset holds a client's cities together with their lookup indexes.
+// Indexes map keys to positions in cities, in dataset order, except for
+// byTimezone, which lists the most populous cities first.
+type dataset struct {
+	cities []CityData
+	owned  bool // Whether cities may be appended to in place
+
+	byName     map[string][]int // Normalized city and alternate names
+	byISO      map[string][]int // Uppercase ISO2 and ISO3 codes
+	byTimezone map[string][]int // Lowercase IANA timezones, by population
+}
+
+// newDataset indexes cities. Unless owned, the slice is copied before the
+// first append, so shared slices such as the bundled dataset stay untouched.
+func newDataset(cities []CityData, pipeline []NormalizationStage, owned bool) *dataset {
+	d := &dataset{
+		cities:     cities,
+		owned:      owned,
+		byISO:      make(map[string][]int),
+		byTimezone: make(map[string][]int),
+	}
+
+	for i := range cities {
+		d.indexISO(i)
+		addIndexKey(d.byTimezone, strings.ToLower(cities[i].Timezone), i)
+	}
+	for _, positions := range d.byTimezone {
+		sort.SliceStable(positions, func(i, j int) bool {
+			return cities[positions[i]].Pop > cities[positions[j]].Pop
+		})
+	}
+	d.reindexNames(pipeline)
+
+	return d
+}
+
+// add appends a city and indexes it
+func (d *dataset) add(city CityData, pipeline []NormalizationStage) {
+	if !d.owned {
+		d.cities = append(make([]CityData, 0, len(d.cities)+1), d.cities...)
+		d.owned = true
+	}
+
+	d.cities = append(d.cities, city)
+	i := len(d.cities) - 1
+	d.indexISO(i)
+	d.indexTimezone(i)
+	d.indexName(i, pipeline)
+}
+
+// reindexNames rebuilds the name index, e.g. after the pipeline changed
+func (d *dataset) reindexNames(pipeline []NormalizationStage) {
+	d.byName = make(map[string][]int, len(d.cities))
+	for i := range d.cities {
+		d.indexName(i, pipeline)
+	}
+}
+
+// indexName adds the city at position i under its normalized names
+func (d *dataset) indexName(i int, pipeline []NormalizationStage) {
+	city := d.cities[i]
+	addIndexKey(d.byName, normalize(pipeline, city.City), i)
+	for _, alt := range city.AlternateNames {
+		addIndexKey(d.byName, normalize(pipeline, alt.Name), i)
+	}
+}
+
+// indexISO adds the city at position i under its country codes
+func (d *dataset) indexISO(i int) {
+	city := d.cities[i]
+	addIndexKey(d.byISO, strings.ToUpper(city.ISO2), i)
+	addIndexKey(d.byISO, strings.ToUpper(city.ISO3), i)
+}
+
+// indexTimezone adds the city at position i under its timezone, after the
+// cities at least as populous
+func (d *dataset) indexTimezone(i int) {
+	key := strings.ToLower(d.cities[i].Timezone)
+	if key == "" {
+		return
+	}
+
+	positions := d.byTimezone[key]
+	at := sort.Search(len(positions), func(k int) bool {
+		return d.cities[positions[k]].Pop < d.cities[i].Pop
+	})
+	positions = append(positions, 0)
+	copy(positions[at+1:], positions[at:])
+	positions[at] = i
+	d.byTimezone[key] = positions
+}
+
+// addIndexKey adds position i under key once. Keys of one city are indexed
+// consecutively, so a duplicate can only be the last position of the key.
+func addIndexKey(index map[string][]int, key string, i int) {
+	if key == "" {
+		return
+	}
+	positions := index[key]
+	if len(positions) > 0 && positions[len(positions)-1] == i {
+		return
+	}
+	index[key] = append(positions, i)
+}
+
+// citiesAt returns the cities at the given positions
+func (d *dataset) citiesAt(positions []int) []CityData {
+	var cities []CityData
+	for _, i := range positions {
+		cities = append(cities, d.cities[i])
+	}
+	return cities
+}
+
+// all returns the cities with their capacity capped, so callers appending
+// to the slice cannot write into the dataset's spare capacity
+func (d *dataset) all() []CityData {
+	return d.cities[:len(d.cities):len(d.cities)]
+}
@@ -0,0 +1,217 @@
+package city
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDataset(t *testing.T) {
+	pipeline := DefaultNormalization()
+
+	t.Run("Indexes names, alternate names and codes", func(t *testing.T) {
+		d := newDataset([]CityData{
+			{City: "Zürich", ISO2: "CH", ISO3: "CHE", AlternateNames: []AlternateName{{Name: "Zurich"}, {Name: "zürich"}}},
+			{City: "Geneva", ISO2: "ch", ISO3: "CHE"},
+		}, pipeline, false)
+
+		if got := d.byName["zurich"]; len(got) != 1 || got[0] != 0 {
+			t.Errorf("Expected alternate name indexed once, got %v", got)
+		}
+		if got := d.byName["zürich"]; len(got) != 1 {
+			t.Errorf("Expected duplicate names indexed once, got %v", got)
+		}
+		if got := d.byISO["CH"]; len(got) != 2 {
+			t.Errorf("Expected 2 cities for CH, got %v", got)
+		}
+	})
+
+	t.Run("Add does not modify a shared slice", func(t *testing.T) {
+		shared := make([]CityData, 1, 10)
+		shared[0] = CityData{City: "Chicago", ISO2: "US"}
+
+		d := newDataset(shared, pipeline, false)
+		d.add(CityData{City: "Boston", ISO2: "US"}, pipeline)
+
+		if len(d.cities) != 2 || d.cities[1].City != "Boston" {
+			t.Fatalf("Expected Boston appended, got %v", d.cities)
+		}
+		if shared[:2][1].City != "" {
+			t.Error("Shared backing array should be untouched")
+		}
+		if got := d.byISO["US"]; len(got) != 2 {
+			t.Errorf("Expected incremental ISO index, got %v", got)
+		}
+		if got := d.byName["boston"]; len(got) != 1 || got[0] != 1 {
+			t.Errorf("Expected incremental name index, got %v", got)
+		}
+	})
+
+	t.Run("Timezone index is ordered by population", func(t *testing.T) {
+		d := newDataset([]CityData{
+			{City: "Aurora", Pop: 180000, Timezone: "America/Chicago"},
+			{City: "Chicago", Pop: 2700000, Timezone: "America/Chicago"},
+			{City: "Naperville", Pop: 180000, Timezone: "america/chicago"},
+		}, pipeline, false)
+		if got := d.byTimezone["america/chicago"]; !reflect.DeepEqual(got, []int{1, 0, 2}) {
+			t.Errorf("Expected most populous first, ties in dataset order, got %v", got)
+		}
+
+		d.add(CityData{City: "Joliet", Pop: 180000, Timezone: "America/Chicago"}, pipeline)
+		d.add(CityData{City: "Milwaukee", Pop: 570000, Timezone: "America/Chicago"}, pipeline)
+		if got := d.byTimezone["america/chicago"]; !reflect.DeepEqual(got, []int{1, 4, 0, 2, 3}) {
+			t.Errorf("Expected added cities inserted by population, got %v", got)
+		}
+	})
+
+	t.Run("All caps capacity", func(t *testing.T) {
+		d := newDataset(make([]CityData, 1, 10), pipeline, true)
+		if cap(d.all()) != 1 {
+			t.Errorf("Expected capacity 1, got %d", cap(d.all()))
+		}
+	})
+}
+
+func TestEmptyDatasetClient(t *testing.T) {
+	t.Run("Queries return empty results", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+
+		cities, err := client.LookupViaCity("Chicago")
+		if err != nil || len(cities) != 0 {
+			t.Errorf("Expected no results and no error, got %d, %v", len(cities), err)
+		}
+		cities, err = client.FindFromIsoCode("US")
+		if err != nil || len(cities) != 0 {
+			t.Errorf("Expected no results and no error, got %d, %v", len(cities), err)
+		}
+		cities, err = client.SearchCities("chicago", DefaultSearchOptions())
+		if err != nil || len(cities) != 0 {
+			t.Errorf("Expected no results and no error, got %d, %v", len(cities), err)
+		}
+		if n, err := client.Len(); err != nil || n != 0 {
+			t.Errorf("Expected empty dataset, got %d, %v", n, err)
+		}
+	})
+
+	t.Run("ErrorOnEmptyDataset", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}, ErrorOnEmptyDataset: true})
+
+		if _, err := client.LookupViaCity("Chicago"); !errors.Is(err, ErrDatasetEmpty) {
+			t.Errorf("Expected ErrDatasetEmpty, got %v", err)
+		}
+		if _, err := client.FindCities(CityQuery{ISO2: "US"}); !errors.Is(err, ErrDatasetEmpty) {
+			t.Errorf("Expected ErrDatasetEmpty, got %v", err)
+		}
+		if _, err := client.Cities(); !errors.Is(err, ErrDatasetEmpty) {
+			t.Errorf("Expected ErrDatasetEmpty, got %v", err)
+		}
+	})
+
+	t.Run("AddCity populates the dataset", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}, ErrorOnEmptyDataset: true})
+
+		if _, err := client.LookupViaCity("Springfield"); !errors.Is(err, ErrDatasetEmpty) {
+			t.Fatalf("Expected ErrDatasetEmpty, got %v", err)
+		}
+
+		err := client.AddCity(CityData{City: "Springfield", ISO2: "US", ISO3: "USA", Province: "Missouri"})
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		cities, err := client.LookupViaCity("springfield")
+		if err != nil || len(cities) != 1 {
+			t.Errorf("Expected 1 result, got %d, %v", len(cities), err)
+		}
+		cities, err = client.FindCities(CityQuery{Province: "missouri", ISO2: "USA"})
+		if err != nil || len(cities) != 1 {
+			t.Errorf("Expected 1 result, got %d, %v", len(cities), err)
+		}
+	})
+
+	t.Run("AddCity clears cached misses", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+
+		if cities, _ := client.LookupViaCity("Boston"); len(cities) != 0 {
+			t.Fatal("Expected no results")
+		}
+		if err := client.AddCity(CityData{City: "Boston"}); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if cities, _ := client.LookupViaCity("Boston"); len(cities) != 1 {
+			t.Errorf("Expected Boston after adding it, got %d", len(cities))
+		}
+	})
+
+	t.Run("AddCity requires a name", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+
+		var validationErr ValidationError
+		if err := client.AddCity(CityData{ISO2: "US"}); !errors.As(err, &validationErr) {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("Reload loads the bundled dataset", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		if err := client.Reload(); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		cities, err := client.LookupViaCity("Chicago")
+		if err != nil || len(cities) == 0 {
+			t.Errorf("Expected Chicago after reload, got %d, %v", len(cities), err)
+		}
+	})
+
+	t.Run("Preload loads the bundled dataset", func(t *testing.T) {
+		client := NewClient(DefaultClientOptions())
+		if err := client.Preload(); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		client.mu.RLock()
+		loaded := client.data != nil
+		client.mu.RUnlock()
+		if !loaded {
+			t.Error("Expected the dataset to be loaded")
+		}
+	})
+
+	t.Run("Adding to a bundled client leaves other clients untouched", func(t *testing.T) {
+		client := NewClient(DefaultClientOptions())
+		if err := client.AddCity(CityData{City: "Atlantis"}); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		if cities, _ := client.LookupViaCity("Atlantis"); len(cities) != 1 {
+			t.Errorf("Expected Atlantis in the client, got %d", len(cities))
+		}
+		if cities, _ := LookupViaCity("Atlantis"); len(cities) != 0 {
+			t.Errorf("Expected no Atlantis in the default client, got %d", len(cities))
+		}
+	})
+
+	t.Run("Concurrent adds and lookups", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_ = client.AddCity(CityData{City: "Metropolis", ISO2: "US"})
+			}()
+			go func() {
+				defer wg.Done()
+				_, _ = client.LookupViaCity("Metropolis")
+			}()
+		}
+		wg.Wait()
+
+		if cities, _ := client.LookupViaCity("Metropolis"); len(cities) != 10 {
+			t.Errorf("Expected 10 cities, got %d", len(cities))
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package city
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Candidate is a city matching an ambiguous name, with a label telling it
+// apart from the other candidates
+type Candidate struct {
+	City  CityData
+	Label string // E.g. "Springfield, MO, US"
+}
+
+// Disambiguate returns the cities named cityName, most populous first, with
+// display labels for pickers. Labels consist of the name, the state code or
+// province, and the country code, and are made unique with coordinates if
+// needed.
+func (c *Client) Disambiguate(cityName string) ([]Candidate, error) {
+	return c.DisambiguateContext(context.Background(), cityName)
+}
+
+// DisambiguateContext is like Disambiguate but passes ctx to the client's hooks
+// and provider
+func (c *Client) DisambiguateContext(ctx context.Context, cityName string) ([]Candidate, error) {
+	trace := c.startLookup(ctx, "Disambiguate", cityName)
+	candidates, err := c.disambiguate(trace.ctx, cityName)
+	trace.end(len(candidates), false, err)
+	return candidates, err
+}
+
+// disambiguate implements Disambiguate
+func (c *Client) disambiguate(ctx context.Context, cityName string) ([]Candidate, error) {
+	cities, _, err := c.lookupViaCity(ctx, cityName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort a copy, the cities may be shared with the cache
+	sorted := append([]CityData(nil), cities...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Pop > sorted[j].Pop
+	})
+
+	candidates := make([]Candidate, len(sorted))
+	labels := make(map[string]int)
+	for i, city := range sorted {
+		candidates[i] = Candidate{City: city, Label: cityLabel(city)}
+		labels[candidates[i].Label]++
+	}
+
+	for i := range candidates {
+		if labels[candidates[i].Label] > 1 {
+			candidates[i].Label += fmt.Sprintf(" (%.2f, %.2f)", candidates[i].City.Lat, candidates[i].City.Lng)
+		}
+	}
+
+	return candidates, nil
+}
+
+// cityLabel returns "City, Region, Country" for a city, where the region is
+// the ANSI state code or else the province, and the country is the ISO2 code
+// or else the ISO3 code. Empty parts are left out.
+func cityLabel(city CityData) string {
+	parts := []string{city.City}
+
+	region := city.StateANSI
+	if region == "" {
+		region = city.Province
+	}
+	if region != "" && region != city.City {
+		parts = append(parts, region)
+	}
+
+	country := city.ISO2
+	if !isValidISO2Code(country) {
+		country = city.ISO3
+	}
+	if country != "" {
+		parts = append(parts, country)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// deduplicate keeps the most populous city per normalized name and country,
+// at the position of the first city of each name and country
+func deduplicate(pipeline []NormalizationStage, cities []CityData) []CityData {
+	type key struct{ name, country string }
+
+	positions := make(map[key]int)
+	var unique []CityData
+	for _, city := range cities {
+		k := key{normalize(pipeline, city.City), strings.ToUpper(city.ISO3)}
+		i, seen := positions[k]
+		if !seen {
+			positions[k] = len(unique)
+			unique = append(unique, city)
+			continue
+		}
+		if city.Pop > unique[i].Pop {
+			unique[i] = city
+		}
+	}
+	return unique
+}
@@ -0,0 +1,100 @@
+package city
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDisambiguate(t *testing.T) {
+	t.Run("Bundled dataset", func(t *testing.T) {
+		candidates, err := Disambiguate("Springfield")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(candidates) < 2 {
+			t.Fatalf("Expected several Springfields, got %d", len(candidates))
+		}
+
+		labels := make(map[string]bool)
+		for i, candidate := range candidates {
+			if labels[candidate.Label] {
+				t.Errorf("Duplicate label %q", candidate.Label)
+			}
+			labels[candidate.Label] = true
+
+			if i > 0 && candidate.City.Pop > candidates[i-1].City.Pop {
+				t.Errorf("Expected candidates by descending population, got %s after %s", candidate.Label, candidates[i-1].Label)
+			}
+		}
+		if !labels["Springfield, MO, US"] {
+			t.Errorf("Expected the label \"Springfield, MO, US\", got %v", labels)
+		}
+	})
+
+	t.Run("Labels", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{
+			{City: "San Jose", ISO2: "US", ISO3: "USA", StateANSI: "CA", Province: "California", Pop: 1000},
+			{City: "San Jose", ISO2: "CR", ISO3: "CRI", Province: "San Jose", Pop: 2000},
+			{City: "San Jose", ISO2: "-99", ISO3: "XXX", Province: "Somewhere", Pop: 50},
+			{City: "San Jose", ISO2: "GT", ISO3: "GTM", Province: "Escuintla", Lat: 13.93, Lng: -90.82, Pop: 20},
+			{City: "San Jose", ISO2: "GT", ISO3: "GTM", Province: "Escuintla", Lat: 14.5, Lng: -90.5, Pop: 10},
+		}})
+
+		candidates, err := client.Disambiguate("san jose")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		var labels []string
+		for _, candidate := range candidates {
+			labels = append(labels, candidate.Label)
+		}
+		expected := []string{
+			"San Jose, CR",
+			"San Jose, CA, US",
+			"San Jose, Somewhere, XXX",
+			"San Jose, Escuintla, GT (13.93, -90.82)",
+			"San Jose, Escuintla, GT (14.50, -90.50)",
+		}
+		if !reflect.DeepEqual(labels, expected) {
+			t.Errorf("Expected %v, got %v", expected, labels)
+		}
+	})
+
+	t.Run("No matches", func(t *testing.T) {
+		candidates, err := Disambiguate("NonExistentCity")
+		if err != nil || len(candidates) != 0 {
+			t.Errorf("Expected no candidates, got %v, %v", candidates, err)
+		}
+	})
+}
+
+func TestSearchCitiesDeduplicate(t *testing.T) {
+	client := NewClient(ClientOptions{Cities: []CityData{
+		{City: "Springfield", ISO3: "USA", Province: "Illinois", Pop: 116000},
+		{City: "Springfield", ISO3: "USA", Province: "Missouri", Pop: 157000},
+		{City: "Springfield", ISO3: "NZL", Pop: 500},
+		{City: "SPRINGFIELD", ISO3: "USA", Province: "Ohio", Pop: 60000},
+	}})
+
+	options := DefaultSearchOptions()
+	options.ExactMatch = true
+	options.Deduplicate = true
+
+	results, err := client.SearchCities("springfield", options)
+	if err != nil {
+		t.Fatalf("Should not error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected one Springfield per country, got %v", results)
+	}
+	if results[0].Province != "Missouri" || results[1].ISO3 != "NZL" {
+		t.Errorf("Expected the most populous US Springfield first, got %v", results)
+	}
+
+	options.Deduplicate = false
+	if results, _ := client.SearchCities("springfield", options); len(results) != 4 {
+		t.Errorf("Expected 4 results without deduplication, got %d", len(results))
+	}
+}
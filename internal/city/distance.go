@@ -0,0 +1,107 @@
+package city
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// EarthRadiusKm is the mean Earth radius used for distance calculations
+const EarthRadiusKm = 6371.0
+
+// DistanceBetween returns the great-circle (haversine) distance between two
+// cities in kilometers
+func DistanceBetween(cityA, cityB CityData) float64 {
+	return haversineKm(cityA.Lat, cityA.Lng, cityB.Lat, cityB.Lng)
+}
+
+// DistanceBetweenNames resolves both city names with LookupViaCity and returns
+// the distance in kilometers between them. Ambiguous names resolve to the
+// most populous matching city.
+func (c *Client) DistanceBetweenNames(a, b string) (float64, error) {
+	cityA, err := c.resolveCity(a)
+	if err != nil {
+		return 0, err
+	}
+
+	cityB, err := c.resolveCity(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return DistanceBetween(cityA, cityB), nil
+}
+
+// NearestCities returns up to limit cities ordered by their great-circle
+// distance to the coordinates, nearest first
+func (c *Client) NearestCities(lat, lng float64, limit int) ([]CityData, error) {
+	return c.NearestCitiesContext(context.Background(), lat, lng, limit)
+}
+
+// NearestCitiesContext is like NearestCities but passes ctx to the client's
+// hooks and provider
+func (c *Client) NearestCitiesContext(ctx context.Context, lat, lng float64, limit int) ([]CityData, error) {
+	trace := c.startLookup(ctx, "NearestCities", fmt.Sprintf("%g,%g", lat, lng))
+	results, err := c.nearestCities(trace.ctx, lat, lng, limit)
+	trace.end(len(results), false, err)
+	return results, err
+}
+
+// nearestCities implements NearestCities
+func (c *Client) nearestCities(ctx context.Context, lat, lng float64, limit int) ([]CityData, error) {
+	if err := ValidateCoordinates(lat, lng); err != nil {
+		return nil, fmt.Errorf("invalid coordinates: %w", err)
+	}
+	if limit < 1 {
+		return nil, NewValidationError("limit", "limit must be at least 1", limit)
+	}
+
+	results, err := c.source().Nearest(ctx, lat, lng, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.notFoundError(fmt.Sprintf("%g,%g", lat, lng), "nearest cities", len(results)); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// resolveCity looks up a city name and returns the most populous match
+func (c *Client) resolveCity(cityName string) (CityData, error) {
+	cities, err := c.LookupViaCity(cityName)
+	if err != nil {
+		return CityData{}, err
+	}
+	if len(cities) == 0 {
+		return CityData{}, NewSearchError(cityName, "resolve city", ErrNotFound)
+	}
+	return mostPopulous(cities), nil
+}
+
+// mostPopulous returns the city with the largest population, preferring the
+// earliest one on ties. cities must not be empty.
+func mostPopulous(cities []CityData) CityData {
+	best := cities[0]
+	for _, city := range cities[1:] {
+		if city.Pop > best.Pop {
+			best = city
+		}
+	}
+	return best
+}
+
+// haversineKm returns the great-circle distance in kilometers between two
+// points given in decimal degrees
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const degToRad = math.Pi / 180
+
+	dLat := (lat2 - lat1) * degToRad
+	dLng := (lng2 - lng1) * degToRad
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*degToRad)*math.Cos(lat2*degToRad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
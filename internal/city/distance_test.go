@@ -0,0 +1,83 @@
+package city
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDistanceBetween(t *testing.T) {
+	t.Run("Same city", func(t *testing.T) {
+		city := CityData{Lat: 41.83, Lng: -87.75}
+		if d := DistanceBetween(city, city); d != 0 {
+			t.Errorf("Expected 0, got %f", d)
+		}
+	})
+
+	t.Run("Known distance", func(t *testing.T) {
+		london := CityData{Lat: 51.5074, Lng: -0.1278}
+		paris := CityData{Lat: 48.8566, Lng: 2.3522}
+
+		if d := DistanceBetween(london, paris); math.Abs(d-343.5) > 2 {
+			t.Errorf("Expected about 343.5 km, got %f", d)
+		}
+	})
+
+	t.Run("Symmetric", func(t *testing.T) {
+		a := CityData{Lat: -33.87, Lng: 151.21}
+		b := CityData{Lat: 40.71, Lng: -74.01}
+
+		if DistanceBetween(a, b) != DistanceBetween(b, a) {
+			t.Error("Distance should be symmetric")
+		}
+	})
+
+	t.Run("Antipodal points", func(t *testing.T) {
+		a := CityData{Lat: 0, Lng: 0}
+		b := CityData{Lat: 0, Lng: 180}
+
+		if d := DistanceBetween(a, b); math.Abs(d-math.Pi*EarthRadiusKm) > 1e-6 {
+			t.Errorf("Expected half circumference, got %f", d)
+		}
+	})
+}
+
+func TestDistanceBetweenNames(t *testing.T) {
+	t.Run("Resolves names", func(t *testing.T) {
+		d, err := DistanceBetweenNames("Chicago", "New York")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if d < 1100 || d > 1200 {
+			t.Errorf("Expected about 1150 km, got %f", d)
+		}
+	})
+
+	t.Run("Ambiguous name uses most populous city", func(t *testing.T) {
+		d, err := DistanceBetweenNames("Paris", "London")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if d > 400 {
+			t.Errorf("Expected Paris, France to London, UK, got %f km", d)
+		}
+	})
+
+	t.Run("Unknown city", func(t *testing.T) {
+		_, err := DistanceBetweenNames("Chicago", "NonExistentCity")
+
+		var searchErr SearchError
+		if !errors.As(err, &searchErr) {
+			t.Fatalf("Expected SearchError, got %v", err)
+		}
+		if searchErr.Query != "NonExistentCity" {
+			t.Errorf("Expected query NonExistentCity, got %s", searchErr.Query)
+		}
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		if _, err := DistanceBetweenNames("<script>", "Chicago"); err == nil {
+			t.Error("Should reject suspicious input")
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package city
+
+import (
+	"fmt"
+	"time"
+)
+
+// DSTDetails describes the daylight saving time rules of a city's timezone for a year
+type DSTDetails struct {
+	City           CityData
+	Timezone       string
+	Year           int
+	ObservesDST    bool          // Whether DST is in effect at any point of the year
+	StandardOffset time.Duration // Smallest UTC offset of the year, outside DST
+	DaylightOffset time.Duration // Largest UTC offset of the year, StandardOffset when DST is not observed
+	Transitions    []DSTTransition
+}
+
+// DSTTransition is a change of UTC offset in a timezone
+type DSTTransition struct {
+	At           time.Time // Instant of the change, in the city's location
+	OffsetBefore time.Duration
+	OffsetAfter  time.Duration
+	ToDST        bool // Whether the transition moves clocks forward to the daylight offset
+}
+
+// NextTransition returns the first transition of the year strictly after t
+func (info DSTDetails) NextTransition(t time.Time) (DSTTransition, bool) {
+	for _, transition := range info.Transitions {
+		if transition.At.After(t) {
+			return transition, true
+		}
+	}
+	return DSTTransition{}, false
+}
+
+// DSTInfo resolves a city name with LookupViaCity (ambiguous names resolve
+// to the most populous match) and returns its DST information for the year
+func (c *Client) DSTInfo(cityName string, year int) (DSTDetails, error) {
+	city, err := c.resolveCity(cityName)
+	if err != nil {
+		return DSTDetails{}, err
+	}
+	return DSTInfoForCity(city, year)
+}
+
+// DSTInfoForCity returns the DST information of the city's timezone for the year
+func DSTInfoForCity(city CityData, year int) (DSTDetails, error) {
+	if city.Timezone == "" {
+		return DSTDetails{}, NewValidationError("timezone", "timezone is empty", city.City)
+	}
+
+	location, err := LoadLocation(city.Timezone)
+	if err != nil {
+		return DSTDetails{}, fmt.Errorf("failed to load timezone %s: %w", city.Timezone, err)
+	}
+
+	info := DSTDetails{
+		City:     city,
+		Timezone: city.Timezone,
+		Year:     year,
+	}
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+	end := time.Date(year+1, time.January, 1, 0, 0, 0, 0, location)
+
+	// Zones with negative DST, such as Europe/Dublin, flag their winter time
+	// as DST, so the offsets are told apart by size rather than by IsDST
+	_, minOffset := start.Zone()
+	maxOffset := minOffset
+	for t := start; t.Before(end); {
+		_, offset := t.Zone()
+		minOffset = min(minOffset, offset)
+		maxOffset = max(maxOffset, offset)
+		if t.IsDST() {
+			info.ObservesDST = true
+		}
+
+		// ZoneBounds reports a zero end for the last, open-ended period
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			break
+		}
+		next = next.In(location)
+
+		_, nextOffset := next.Zone()
+		info.Transitions = append(info.Transitions, DSTTransition{
+			At:           next,
+			OffsetBefore: time.Duration(offset) * time.Second,
+			OffsetAfter:  time.Duration(nextOffset) * time.Second,
+		})
+		t = next
+	}
+
+	info.StandardOffset = time.Duration(minOffset) * time.Second
+	info.DaylightOffset = time.Duration(maxOffset) * time.Second
+	if minOffset != maxOffset {
+		info.ObservesDST = true
+	}
+	for i := range info.Transitions {
+		transition := &info.Transitions[i]
+		transition.ToDST = transition.OffsetAfter > transition.OffsetBefore && transition.OffsetAfter == info.DaylightOffset
+	}
+
+	return info, nil
+}
@@ -0,0 +1,131 @@
+package city
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDSTInfo(t *testing.T) {
+	t.Run("Northern hemisphere city with DST", func(t *testing.T) {
+		info, err := DSTInfo("Chicago", 2024)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if !info.ObservesDST {
+			t.Error("Chicago should observe DST")
+		}
+		if info.StandardOffset != -6*time.Hour || info.DaylightOffset != -5*time.Hour {
+			t.Errorf("Expected -6h/-5h, got %v/%v", info.StandardOffset, info.DaylightOffset)
+		}
+		if len(info.Transitions) != 2 {
+			t.Fatalf("Expected 2 transitions, got %d", len(info.Transitions))
+		}
+
+		spring := info.Transitions[0]
+		if !spring.ToDST || spring.At.Month() != time.March || spring.At.Day() != 10 {
+			t.Errorf("Expected DST to start on March 10, got %v (ToDST=%v)", spring.At, spring.ToDST)
+		}
+		fall := info.Transitions[1]
+		if fall.ToDST || fall.At.Month() != time.November || fall.At.Day() != 3 {
+			t.Errorf("Expected DST to end on November 3, got %v (ToDST=%v)", fall.At, fall.ToDST)
+		}
+		if fall.OffsetBefore != -5*time.Hour || fall.OffsetAfter != -6*time.Hour {
+			t.Errorf("Expected -5h -> -6h, got %v -> %v", fall.OffsetBefore, fall.OffsetAfter)
+		}
+	})
+
+	t.Run("City without DST", func(t *testing.T) {
+		info, err := DSTInfo("Tokyo", 2024)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if info.ObservesDST {
+			t.Error("Tokyo should not observe DST")
+		}
+		if info.StandardOffset != 9*time.Hour || info.DaylightOffset != 9*time.Hour {
+			t.Errorf("Expected 9h/9h, got %v/%v", info.StandardOffset, info.DaylightOffset)
+		}
+		if len(info.Transitions) != 0 {
+			t.Errorf("Expected no transitions, got %d", len(info.Transitions))
+		}
+	})
+
+	t.Run("Southern hemisphere city", func(t *testing.T) {
+		info, err := DSTInfo("Sydney", 2024)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if info.City.ISO2 != "AU" {
+			t.Errorf("Expected Sydney, Australia, got %s", info.City.ISO2)
+		}
+		if info.StandardOffset != 10*time.Hour || info.DaylightOffset != 11*time.Hour {
+			t.Errorf("Expected 10h/11h, got %v/%v", info.StandardOffset, info.DaylightOffset)
+		}
+		if len(info.Transitions) != 2 || info.Transitions[0].ToDST {
+			t.Errorf("Expected DST to end first, got %v", info.Transitions)
+		}
+	})
+
+	t.Run("Negative DST", func(t *testing.T) {
+		// Europe/Dublin flags winter time as DST with a negative save; the
+		// standard offset is still the smaller one
+		for _, timezone := range []string{"Europe/Dublin", "Europe/London"} {
+			info, err := DSTInfoForCity(CityData{City: "Dublin", Timezone: timezone}, 2024)
+			if err != nil {
+				t.Fatalf("Should not error: %v", err)
+			}
+			if !info.ObservesDST {
+				t.Errorf("%s: expected DST to be observed", timezone)
+			}
+			if info.StandardOffset != 0 || info.DaylightOffset != time.Hour {
+				t.Errorf("%s: expected 0h/1h, got %v/%v", timezone, info.StandardOffset, info.DaylightOffset)
+			}
+			if len(info.Transitions) != 2 || !info.Transitions[0].ToDST || info.Transitions[1].ToDST {
+				t.Errorf("%s: expected DST to start in March and end in October, got %v", timezone, info.Transitions)
+			}
+		}
+	})
+
+	t.Run("Unknown city", func(t *testing.T) {
+		_, err := DSTInfo("NonExistentCity", 2024)
+
+		var searchErr SearchError
+		if !errors.As(err, &searchErr) {
+			t.Errorf("Expected SearchError, got %v", err)
+		}
+	})
+}
+
+func TestDSTInfoForCity(t *testing.T) {
+	t.Run("Empty timezone", func(t *testing.T) {
+		_, err := DSTInfoForCity(CityData{City: "Station"}, 2024)
+
+		var validationErr ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("Invalid timezone", func(t *testing.T) {
+		if _, err := DSTInfoForCity(CityData{Timezone: "Invalid/Zone"}, 2024); err == nil {
+			t.Error("Should reject invalid timezone")
+		}
+	})
+
+	t.Run("NextTransition", func(t *testing.T) {
+		info, err := DSTInfoForCity(CityData{Timezone: "Europe/Berlin"}, 2024)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		next, ok := info.NextTransition(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC))
+		if !ok || next.At.Month() != time.October {
+			t.Errorf("Expected October transition, got %v", next.At)
+		}
+
+		if _, ok := info.NextTransition(time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)); ok {
+			t.Error("Expected no transition after the last one of the year")
+		}
+	})
+}
@@ -1,9 +1,31 @@
 package city
 
 import (
+	"errors"
 	"fmt"
 )
 
+// Sentinel errors for use with errors.Is
+var (
+	// ErrInvalidInput matches every ValidationError
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrInvalidISOCode matches ValidationErrors for malformed ISO country codes
+	ErrInvalidISOCode = errors.New("invalid ISO code")
+
+	// ErrNotFound is returned when nothing matches a query that requires a
+	// result, or any query on a client created with ErrorOnNotFound
+	ErrNotFound = errors.New("no cities found")
+
+	// ErrDatasetEmpty is returned by queries on a client created with
+	// ErrorOnEmptyDataset while its dataset has no cities
+	ErrDatasetEmpty = errors.New("city dataset is empty")
+
+	// ErrProviderManaged is returned by the methods changing a client's
+	// dataset when a DataProvider serves its cities
+	ErrProviderManaged = errors.New("city dataset is managed by the provider")
+)
+
 // Error types for better error handling and debugging
 
 // DataLoadError represents an error loading city data
@@ -49,6 +71,19 @@ func (e ValidationError) Error() string {
 	return fmt.Sprintf("validation error for field '%s': %s", e.Field, e.Message)
 }
 
+// Is reports whether the error matches ErrInvalidInput, or ErrInvalidISOCode
+// for errors on the isoCode field
+func (e ValidationError) Is(target error) bool {
+	switch target {
+	case ErrInvalidInput:
+		return true
+	case ErrInvalidISOCode:
+		return e.Field == "isoCode"
+	default:
+		return false
+	}
+}
+
 // CacheError represents a cache operation error
 type CacheError struct {
 	Operation string
@@ -97,3 +97,103 @@ func TestCacheError(t *testing.T) {
 		}
 	})
 }
+
+func TestSentinelErrors(t *testing.T) {
+	t.Run("Invalid input", func(t *testing.T) {
+		_, err := LookupViaCity("<script>alert('xss')</script>")
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput, got %v", err)
+		}
+		if errors.Is(err, ErrInvalidISOCode) {
+			t.Error("Input errors should not match ErrInvalidISOCode")
+		}
+	})
+
+	t.Run("Invalid ISO code", func(t *testing.T) {
+		_, err := FindFromIsoCode("INVALID")
+		if !errors.Is(err, ErrInvalidISOCode) {
+			t.Errorf("Expected ErrInvalidISOCode, got %v", err)
+		}
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("ISO code errors should also match ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("Not found when resolving a city", func(t *testing.T) {
+		_, err := DistanceBetweenNames("Chicago", "NonExistentCity")
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Unrelated errors do not match", func(t *testing.T) {
+		err := NewValidationError("isoCode", "bad", nil)
+		if errors.Is(err, ErrNotFound) {
+			t.Error("ValidationError should not match ErrNotFound")
+		}
+	})
+}
+
+func TestErrorOnNotFound(t *testing.T) {
+	client := NewClient(ClientOptions{ErrorOnNotFound: true})
+
+	t.Run("No results", func(t *testing.T) {
+		if _, err := client.LookupViaCity("NonExistentCity"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("LookupViaCity: expected ErrNotFound, got %v", err)
+		}
+		// A cached miss reports the same error
+		if _, err := client.LookupViaCity("NonExistentCity"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("LookupViaCity (cached): expected ErrNotFound, got %v", err)
+		}
+		if _, err := client.FindFromCityStateProvince("NonExistentCity"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("FindFromCityStateProvince: expected ErrNotFound, got %v", err)
+		}
+		if _, err := client.FindFromIsoCode("XX"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("FindFromIsoCode: expected ErrNotFound, got %v", err)
+		}
+		if _, err := client.SearchCities("NonExistentCity", DefaultSearchOptions()); !errors.Is(err, ErrNotFound) {
+			t.Errorf("SearchCities: expected ErrNotFound, got %v", err)
+		}
+		if _, err := client.FindCities(CityQuery{City: "Springfield", ISO2: "DE"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("FindCities: expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Empty input returns no results", func(t *testing.T) {
+		cities, err := client.LookupViaCity("  ")
+		if err != nil || len(cities) != 0 {
+			t.Errorf("Expected no results and no error, got %v, %v", cities, err)
+		}
+	})
+
+	t.Run("Results are returned normally", func(t *testing.T) {
+		cities, err := client.LookupViaCity("Chicago")
+		if err != nil || len(cities) == 0 {
+			t.Errorf("Expected Chicago, got %d, %v", len(cities), err)
+		}
+	})
+}
+
+func TestErrorOnEmptyInput(t *testing.T) {
+	client := NewClient(ClientOptions{ErrorOnEmptyInput: true})
+
+	t.Run("Empty input is invalid", func(t *testing.T) {
+		_, err := client.LookupViaCity("  ")
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput, got %v", err)
+		}
+		if errors.Is(err, ErrNotFound) {
+			t.Error("Empty input should not match ErrNotFound")
+		}
+		if _, err := client.FindFromIsoCode(""); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("FindFromIsoCode: expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("No results are not an error", func(t *testing.T) {
+		cities, err := client.LookupViaCity("NonExistentCity")
+		if err != nil || len(cities) != 0 {
+			t.Errorf("Expected no results and no error, got %v, %v", cities, err)
+		}
+	})
+}
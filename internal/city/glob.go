@@ -0,0 +1,93 @@
+package city
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Glob wildcards for SearchOptions.Glob
+const (
+	globAnyRun  = '*' // Matches any sequence of characters, including none
+	globAnyRune = '?' // Matches exactly one character
+)
+
+// normalizeGlob runs the literal parts of a glob pattern through the
+// pipeline, keeping the wildcards, which stages such as StripPunctuationStage
+// would otherwise remove. Whitespace next to a wildcard is preserved, so
+// "san *" still requires a word boundary.
+func normalizeGlob(pipeline []NormalizationStage, pattern string) string {
+	var b strings.Builder
+	literalStart := 0
+
+	flush := func(end int) {
+		literal := pattern[literalStart:end]
+		if literal == "" {
+			return
+		}
+
+		first, _ := utf8.DecodeRuneInString(literal)
+		last, _ := utf8.DecodeLastRuneInString(literal)
+		leading := unicode.IsSpace(first) && literalStart > 0
+		trailing := unicode.IsSpace(last) && end < len(pattern)
+
+		normalized := strings.TrimSpace(normalize(pipeline, literal))
+		if normalized == "" {
+			if leading || trailing {
+				b.WriteByte(' ')
+			}
+			return
+		}
+
+		if leading {
+			b.WriteByte(' ')
+		}
+		b.WriteString(normalized)
+		if trailing {
+			b.WriteByte(' ')
+		}
+	}
+
+	for i, r := range pattern {
+		if r == globAnyRun || r == globAnyRune {
+			flush(i)
+			b.WriteRune(r)
+			literalStart = i + 1
+		}
+	}
+	flush(len(pattern))
+
+	return b.String()
+}
+
+// globMatch reports whether the whole of s matches the pattern, where '*'
+// matches any sequence of characters and '?' exactly one. It backtracks only
+// to the most recent '*', so matching takes O(len(pattern) * len(s)) time.
+func globMatch(pattern, s string) bool {
+	p := []rune(pattern)
+	r := []rune(s)
+
+	pi, ri := 0, 0
+	starP, starR := -1, 0
+	for ri < len(r) {
+		switch {
+		case pi < len(p) && (p[pi] == globAnyRune || p[pi] == r[ri]):
+			pi++
+			ri++
+		case pi < len(p) && p[pi] == globAnyRun:
+			starP, starR = pi, ri
+			pi++
+		case starP >= 0:
+			// Let the last '*' absorb one more character
+			starR++
+			pi, ri = starP+1, starR
+		default:
+			return false
+		}
+	}
+
+	for pi < len(p) && p[pi] == globAnyRun {
+		pi++
+	}
+	return pi == len(p)
+}
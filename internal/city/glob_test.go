@@ -0,0 +1,124 @@
+package city
+
+import (
+	"testing"
+)
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		input   string
+		want    bool
+	}{
+		{"san *o", "san francisco", true},
+		{"san *o", "san diego", true},
+		{"san *o", "san jose", false},
+		{"new?rk", "newark", true},
+		{"new?rk", "new york", false},
+		{"new*rk", "new york", true},
+		{"*", "", true},
+		{"?", "", false},
+		{"", "", true},
+		{"", "a", false},
+		{"chicago", "chicago", true},
+		{"chicago", "chicago heights", false},
+		{"*ago", "chicago", true},
+		{"m?nchen", "münchen", true},
+		{"a*b*c", "aXbYbZc", true},
+		{"a*b*c", "aXbYbZ", false},
+		{"**a", "a", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.input, func(t *testing.T) {
+			if got := globMatch(tt.pattern, tt.input); got != tt.want {
+				t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeGlob(t *testing.T) {
+	pipeline := []NormalizationStage{LowercaseStage(), StripPunctuationStage()}
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"San *O", "san *o"},
+		{"New?rk", "new?rk"},
+		{"St. *", "st *"},
+		{"* Springs", "* springs"},
+		{"san  *  o", "san * o"},
+		{"*", "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := normalizeGlob(pipeline, tt.pattern); got != tt.want {
+				t.Errorf("normalizeGlob(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchCitiesGlob(t *testing.T) {
+	options := DefaultSearchOptions()
+	options.Glob = true
+
+	t.Run("Star wildcard", func(t *testing.T) {
+		cities, err := SearchCities("san *o", options)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		found := false
+		for _, city := range cities {
+			if city.City == "San Francisco" {
+				found = true
+			}
+			if city.City == "San Jose" {
+				t.Error("San Jose should not match san *o")
+			}
+		}
+		if !found {
+			t.Error("Expected San Francisco")
+		}
+	})
+
+	t.Run("Question mark wildcard", func(t *testing.T) {
+		cities, err := SearchCities("new?rk", options)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) == 0 {
+			t.Fatal("Expected Newark")
+		}
+		for _, city := range cities {
+			if city.City == "New York" {
+				t.Error("New York should not match new?rk")
+			}
+		}
+	})
+
+	t.Run("Patterns match whole fields", func(t *testing.T) {
+		cities, _ := SearchCities("chicag?", options)
+		for _, city := range cities {
+			if city.City == "Chicago Heights" {
+				t.Error("Chicago Heights should not match chicag?")
+			}
+		}
+	})
+
+	t.Run("Case-sensitive", func(t *testing.T) {
+		options := options
+		options.CaseSensitive = true
+
+		if cities, _ := SearchCities("chic*", options); len(cities) != 0 {
+			t.Errorf("Expected no match for lowercase pattern, got %d", len(cities))
+		}
+		if cities, _ := SearchCities("Chic*", options); len(cities) == 0 {
+			t.Error("Expected Chicago")
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package city
+
+import (
+	"cmp"
+	"sort"
+)
+
+// Group is a key with its cities, see SortedGroups
+type Group[K cmp.Ordered] struct {
+	Key    K
+	Cities []CityData
+}
+
+// GroupBy groups cities by the key returned for each city. Each group keeps
+// the order of cities.
+func GroupBy[K comparable](cities []CityData, key func(CityData) K) map[K][]CityData {
+	groups := make(map[K][]CityData)
+	for _, city := range cities {
+		k := key(city)
+		groups[k] = append(groups[k], city)
+	}
+	return groups
+}
+
+// GroupByCountry groups cities by their ISO3 country code. ISO2 codes are not
+// unique, since territories without one share the placeholder "-99".
+func GroupByCountry(cities []CityData) map[string][]CityData {
+	return GroupBy(cities, func(city CityData) string { return city.ISO3 })
+}
+
+// GroupByTimezone groups cities by their IANA timezone. Cities without a
+// timezone are grouped under "".
+func GroupByTimezone(cities []CityData) map[string][]CityData {
+	return GroupBy(cities, func(city CityData) string { return city.Timezone })
+}
+
+// SortedGroups returns the groups ordered by key, for stable output
+func SortedGroups[K cmp.Ordered](groups map[K][]CityData) []Group[K] {
+	sorted := make([]Group[K], 0, len(groups))
+	for key, cities := range groups {
+		sorted = append(sorted, Group[K]{Key: key, Cities: cities})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return cmp.Less(sorted[i].Key, sorted[j].Key)
+	})
+	return sorted
+}
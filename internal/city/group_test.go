@@ -0,0 +1,85 @@
+package city
+
+import (
+	"reflect"
+	"testing"
+)
+
+var groupCities = []CityData{
+	{City: "Chicago", ISO3: "USA", Timezone: "America/Chicago", Pop: 5915976},
+	{City: "Berlin", ISO3: "DEU", Timezone: "Europe/Berlin", Pop: 3406000},
+	{City: "Milwaukee", ISO3: "USA", Timezone: "America/Chicago", Pop: 1388000},
+	{City: "Pristina", ISO2: "-99", ISO3: "KOS", Timezone: "Europe/Belgrade", Pop: 331700},
+	{City: "Lefkosa", ISO2: "-99", ISO3: "CYN", Timezone: "Asia/Nicosia", Pop: 200000},
+	{City: "Artigas Base", ISO3: "ATA", Pop: 34.5},
+}
+
+func cityNames(cities []CityData) []string {
+	names := make([]string, len(cities))
+	for i, city := range cities {
+		names[i] = city.City
+	}
+	return names
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Run("Custom key", func(t *testing.T) {
+		groups := GroupBy(groupCities, func(city CityData) SizeClass { return city.SizeClass() })
+		if names := cityNames(groups[SizeClassLarge]); !reflect.DeepEqual(names, []string{"Chicago", "Berlin", "Milwaukee"}) {
+			t.Errorf("Expected the large cities in input order, got %v", names)
+		}
+		if len(groups[SizeClassSmall]) != 1 {
+			t.Errorf("Expected 1 small city, got %d", len(groups[SizeClassSmall]))
+		}
+	})
+
+	t.Run("Empty input", func(t *testing.T) {
+		if groups := GroupBy(nil, func(city CityData) string { return city.City }); len(groups) != 0 {
+			t.Errorf("Expected no groups, got %v", groups)
+		}
+	})
+}
+
+func TestGroupByCountry(t *testing.T) {
+	groups := GroupByCountry(groupCities)
+
+	// Territories sharing the ISO2 placeholder stay apart
+	if len(groups) != 5 || len(groups["KOS"]) != 1 || len(groups["CYN"]) != 1 {
+		t.Errorf("Expected 5 countries, got %v", groups)
+	}
+	if names := cityNames(groups["USA"]); !reflect.DeepEqual(names, []string{"Chicago", "Milwaukee"}) {
+		t.Errorf("Expected Chicago and Milwaukee, got %v", names)
+	}
+}
+
+func TestGroupByTimezone(t *testing.T) {
+	groups := GroupByTimezone(groupCities)
+
+	if len(groups["America/Chicago"]) != 2 {
+		t.Errorf("Expected 2 cities in America/Chicago, got %d", len(groups["America/Chicago"]))
+	}
+	if names := cityNames(groups[""]); !reflect.DeepEqual(names, []string{"Artigas Base"}) {
+		t.Errorf("Expected cities without a timezone under \"\", got %v", names)
+	}
+}
+
+func TestSortedGroups(t *testing.T) {
+	sorted := SortedGroups(GroupByTimezone(groupCities))
+
+	var keys []string
+	for _, group := range sorted {
+		keys = append(keys, group.Key)
+	}
+	expected := []string{"", "America/Chicago", "Asia/Nicosia", "Europe/Belgrade", "Europe/Berlin"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected %v, got %v", expected, keys)
+	}
+	if len(sorted[1].Cities) != 2 {
+		t.Errorf("Expected 2 cities in America/Chicago, got %d", len(sorted[1].Cities))
+	}
+
+	byPopulation := SortedGroups(GroupBy(groupCities, func(city CityData) float64 { return city.Pop }))
+	if byPopulation[0].Key != 34.5 {
+		t.Errorf("Expected numeric keys in ascending order, got %v first", byPopulation[0].Key)
+	}
+}
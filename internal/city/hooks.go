@@ -0,0 +1,89 @@
+package city
+
+import (
+	"context"
+	"time"
+)
+
+// Hooks instruments a Client's lookups, e.g. for tracing or metrics.
+// Implementations must be safe for concurrent use. Each lookup has a Context
+// variant, such as LookupViaCityContext, whose ctx is passed to OnLookupStart
+// and on to the client's provider, so lookup spans join the caller's trace.
+type Hooks interface {
+	// OnLookupStart is called before a lookup runs. The returned context is
+	// passed to OnLookupEnd, so it can carry state such as a span.
+	OnLookupStart(ctx context.Context, event LookupStart) context.Context
+
+	// OnLookupEnd is called after the lookup finished
+	OnLookupEnd(ctx context.Context, event LookupEnd)
+}
+
+// LookupStart describes a lookup that is about to run
+type LookupStart struct {
+	Function string // Name of the lookup function, e.g. "LookupViaCity"
+	Query    string // The query as passed to the function
+}
+
+// LookupEnd describes a finished lookup
+type LookupEnd struct {
+	LookupStart
+
+	Results  int           // Number of results
+	Duration time.Duration // Time taken by the lookup, excluding the hooks
+	CacheHit bool          // Whether the result was served from the cache
+	Err      error         // Error returned by the lookup, if any
+}
+
+// hooksHolder wraps Hooks for storage in an atomic.Pointer
+type hooksHolder struct {
+	hooks Hooks
+}
+
+// SetHooks sets the hooks instrumenting the client's lookups, nil removes them
+func (c *Client) SetHooks(hooks Hooks) {
+	if hooks == nil {
+		c.hooks.Store(nil)
+		return
+	}
+	c.hooks.Store(&hooksHolder{hooks: hooks})
+}
+
+// lookupTrace is an instrumented lookup in progress
+type lookupTrace struct {
+	hooks Hooks
+	ctx   context.Context
+	start LookupStart
+	begin time.Time
+}
+
+// startLookup reports the start of a lookup with the caller's ctx to the
+// client's hooks. The trace's context is passed on to the client's provider.
+// Without hooks, the returned trace's end does nothing.
+func (c *Client) startLookup(ctx context.Context, function, query string) lookupTrace {
+	holder := c.hooks.Load()
+	if holder == nil {
+		return lookupTrace{ctx: ctx}
+	}
+
+	start := LookupStart{Function: function, Query: query}
+	if hooked := holder.hooks.OnLookupStart(ctx, start); hooked != nil {
+		ctx = hooked
+	}
+
+	return lookupTrace{hooks: holder.hooks, ctx: ctx, start: start, begin: time.Now()}
+}
+
+// end reports the outcome of the lookup to the hooks it started with
+func (t lookupTrace) end(results int, cacheHit bool, err error) {
+	if t.hooks == nil {
+		return
+	}
+
+	t.hooks.OnLookupEnd(t.ctx, LookupEnd{
+		LookupStart: t.start,
+		Results:     results,
+		Duration:    time.Since(t.begin),
+		CacheHit:    cacheHit,
+		Err:         err,
+	})
+}
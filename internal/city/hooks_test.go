@@ -0,0 +1,216 @@
+package city
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type hooksContextKey struct{}
+
+// callerContextKey marks the context passed to the Context variants
+type callerContextKey struct{}
+
+// recordingHooks records the events it receives
+type recordingHooks struct {
+	mu      sync.Mutex
+	starts  []LookupStart
+	ends    []LookupEnd
+	ctxOK   []bool
+	callers []interface{}
+}
+
+func (h *recordingHooks) OnLookupStart(ctx context.Context, event LookupStart) context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.starts = append(h.starts, event)
+	h.callers = append(h.callers, ctx.Value(callerContextKey{}))
+	return context.WithValue(ctx, hooksContextKey{}, event.Function)
+}
+
+func (h *recordingHooks) OnLookupEnd(ctx context.Context, event LookupEnd) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ends = append(h.ends, event)
+	h.ctxOK = append(h.ctxOK, ctx.Value(hooksContextKey{}) == event.Function)
+}
+
+func (h *recordingHooks) last(t *testing.T) LookupEnd {
+	t.Helper()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.ends) == 0 {
+		t.Fatal("Expected a lookup event")
+	}
+	if !h.ctxOK[len(h.ctxOK)-1] {
+		t.Error("Expected the context returned by OnLookupStart in OnLookupEnd")
+	}
+	return h.ends[len(h.ends)-1]
+}
+
+func TestHooks(t *testing.T) {
+	hooks := &recordingHooks{}
+	client := NewClient(ClientOptions{
+		Cities: []CityData{
+			{City: "Chicago", ISO2: "US", ISO3: "USA", Province: "Illinois", Timezone: "America/Chicago"},
+			{City: "Springfield", ISO2: "US", ISO3: "USA", Province: "Illinois", Timezone: "America/Chicago"},
+			{City: "Springfield", ISO2: "US", ISO3: "USA", Province: "Missouri", Timezone: "America/Chicago"},
+		},
+		CacheSize: 10,
+		Hooks:     hooks,
+	})
+
+	t.Run("Lookup and cache hit", func(t *testing.T) {
+		if _, err := client.LookupViaCity("Springfield"); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		event := hooks.last(t)
+		if event.Function != "LookupViaCity" || event.Query != "Springfield" || event.Results != 2 || event.CacheHit {
+			t.Errorf("Unexpected event %+v", event)
+		}
+
+		_, _ = client.LookupViaCity("Springfield")
+		if event := hooks.last(t); !event.CacheHit {
+			t.Errorf("Expected a cache hit, got %+v", event)
+		}
+	})
+
+	t.Run("Functions", func(t *testing.T) {
+		tests := []struct {
+			function string
+			lookup   func()
+			results  int
+		}{
+			{"FindFromCityStateProvince", func() { _, _ = client.FindFromCityStateProvince("springfield") }, 2},
+			{"FindFromCityStateProvinceScored", func() { _, _ = client.FindFromCityStateProvinceScored("chicago") }, 1},
+			{"FindFromIsoCode", func() { _, _ = client.FindFromIsoCode("US") }, 3},
+			{"FindCities", func() { _, _ = client.FindCities(CityQuery{City: "Springfield", Province: "Missouri"}) }, 1},
+			{"SearchCities", func() { _, _ = client.SearchCities("illinois", DefaultSearchOptions()) }, 2},
+			{"ListProvinces", func() { _, _ = client.ListProvinces("US") }, 2},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.function, func(t *testing.T) {
+				hooks.mu.Lock()
+				before := len(hooks.ends)
+				hooks.mu.Unlock()
+
+				tt.lookup()
+
+				// Functions built on other lookups report a single event
+				hooks.mu.Lock()
+				events := len(hooks.ends) - before
+				hooks.mu.Unlock()
+				if events != 1 {
+					t.Errorf("Expected 1 event, got %d", events)
+				}
+
+				event := hooks.last(t)
+				if event.Function != tt.function || event.Results != tt.results {
+					t.Errorf("Expected %s with %d results, got %+v", tt.function, tt.results, event)
+				}
+			})
+		}
+	})
+
+	t.Run("Errors are reported", func(t *testing.T) {
+		_, _ = client.FindFromIsoCode("INVALID")
+		if event := hooks.last(t); !errors.Is(event.Err, ErrInvalidISOCode) {
+			t.Errorf("Expected ErrInvalidISOCode, got %+v", event)
+		}
+	})
+
+	t.Run("Removing hooks", func(t *testing.T) {
+		client.SetHooks(nil)
+		hooks.mu.Lock()
+		before := len(hooks.ends)
+		hooks.mu.Unlock()
+
+		_, _ = client.LookupViaCity("Chicago")
+
+		hooks.mu.Lock()
+		defer hooks.mu.Unlock()
+		if len(hooks.ends) != before {
+			t.Error("Expected no events after removing the hooks")
+		}
+	})
+}
+
+func TestLookupContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), callerContextKey{}, "request")
+	provider := &recordingProvider{}
+	hooks := &recordingHooks{}
+	client := NewClient(ClientOptions{Provider: provider, Hooks: hooks})
+
+	lookups := map[string]func(){
+		"LookupViaCity":                   func() { _, _ = client.LookupViaCityContext(ctx, "Chicago") },
+		"FindFromCityStateProvince":       func() { _, _ = client.FindFromCityStateProvinceContext(ctx, "chicago") },
+		"FindFromCityStateProvinceScored": func() { _, _ = client.FindFromCityStateProvinceScoredContext(ctx, "chicago") },
+		"FindFromIsoCode":                 func() { _, _ = client.FindFromIsoCodeContext(ctx, "US") },
+		"FindFromTimezone":                func() { _, _ = client.FindFromTimezoneContext(ctx, "America/Chicago") },
+		"SearchCities":                    func() { _, _ = client.SearchCitiesContext(ctx, "chicago", DefaultSearchOptions()) },
+		"FindCities":                      func() { _, _ = client.FindCitiesContext(ctx, CityQuery{City: "Chicago"}) },
+		"ListProvinces":                   func() { _, _ = client.ListProvincesContext(ctx, "US") },
+		"NearestCities":                   func() { _, _ = client.NearestCitiesContext(ctx, 41.88, -87.63, 1) },
+		"Disambiguate":                    func() { _, _ = client.DisambiguateContext(ctx, "Milwaukee") },
+		"CitiesInSameTimezone":            func() { _, _ = client.CitiesInSameTimezoneContext(ctx, "Milwaukee", 1) },
+		"LookupViaAirportCode":            func() { _, _ = client.LookupViaAirportCodeContext(ctx, "ORD") },
+	}
+
+	for function, lookup := range lookups {
+		t.Run(function, func(t *testing.T) {
+			client.RefreshProvider()
+			lookup()
+
+			hooks.mu.Lock()
+			start := hooks.starts[len(hooks.starts)-1]
+			caller := hooks.callers[len(hooks.callers)-1]
+			hooks.mu.Unlock()
+			if start.Function != function || caller != "request" {
+				t.Errorf("Expected OnLookupStart of %s with the caller's context, got %s with %v", function, start.Function, caller)
+			}
+
+			provider.mu.Lock()
+			defer provider.mu.Unlock()
+			if provider.caller != "request" || provider.ctxKey != function {
+				t.Errorf("Expected the provider to get the caller's context through the hooks, got %v, %v", provider.caller, provider.ctxKey)
+			}
+		})
+	}
+
+	t.Run("Without hooks", func(t *testing.T) {
+		provider := &recordingProvider{}
+		client := NewClient(ClientOptions{Provider: provider})
+		_, _ = client.SearchCitiesContext(ctx, "chicago", DefaultSearchOptions())
+
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+		if provider.caller != "request" {
+			t.Errorf("Expected the caller's context, got %v", provider.caller)
+		}
+	})
+}
+
+func BenchmarkLookupViaCityHooks(b *testing.B) {
+	cities := []CityData{{City: "Chicago", Timezone: "America/Chicago"}}
+
+	b.Run("None", func(b *testing.B) {
+		client := NewClient(ClientOptions{Cities: cities, CacheSize: 10})
+		for i := 0; i < b.N; i++ {
+			_, _ = client.LookupViaCity("Chicago")
+		}
+	})
+
+	b.Run("NoOp", func(b *testing.B) {
+		client := NewClient(ClientOptions{Cities: cities, CacheSize: 10, Hooks: nopHooks{}})
+		for i := 0; i < b.N; i++ {
+			_, _ = client.LookupViaCity("Chicago")
+		}
+	})
+}
+
+type nopHooks struct{}
+
+func (nopHooks) OnLookupStart(ctx context.Context, _ LookupStart) context.Context { return ctx }
+func (nopHooks) OnLookupEnd(context.Context, LookupEnd)                           {}
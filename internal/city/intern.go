@@ -0,0 +1,47 @@
+package city
+
+// stringInterner deduplicates strings, so records sharing a value such as a
+// country, province or timezone share one copy instead of holding their own
+type stringInterner struct {
+	strings map[string]string
+}
+
+// newStringInterner creates an interner. It is only needed while decoding and
+// can be discarded afterwards; the interned strings remain shared.
+func newStringInterner() *stringInterner {
+	return &stringInterner{strings: make(map[string]string)}
+}
+
+// intern returns the shared copy of s. A nil interner returns s unchanged.
+func (in *stringInterner) intern(s string) string {
+	if in == nil || s == "" {
+		return s
+	}
+	if shared, ok := in.strings[s]; ok {
+		return shared
+	}
+	in.strings[s] = s
+	return s
+}
+
+// internCity replaces the city's strings with their shared copies
+func (in *stringInterner) internCity(city *CityData) {
+	if in == nil {
+		return
+	}
+
+	city.City = in.intern(city.City)
+	city.ISO2 = in.intern(city.ISO2)
+	city.ISO3 = in.intern(city.ISO3)
+	city.Country = in.intern(city.Country)
+	city.Timezone = in.intern(city.Timezone)
+	city.Province = in.intern(city.Province)
+	city.ExactCity = in.intern(city.ExactCity)
+	city.CityASCII = in.intern(city.CityASCII)
+	city.StateANSI = in.intern(city.StateANSI)
+	city.ExactProvince = in.intern(city.ExactProvince)
+
+	for i := range city.AlternateNames {
+		city.AlternateNames[i].Lang = in.intern(city.AlternateNames[i].Lang)
+	}
+}
@@ -0,0 +1,75 @@
+package city
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// cityDataJSON is the JSON form of CityData in the format of the
+// city-timezones npm package
+type cityDataJSON struct {
+	City          string      `json:"city"`
+	CityASCII     string      `json:"city_ascii"`
+	Lat           float64     `json:"lat"`
+	Lng           float64     `json:"lng"`
+	Pop           float64     `json:"pop"`
+	Country       string      `json:"country"`
+	ISO2          interface{} `json:"iso2"` // String, or number for codes like -99
+	ISO3          string      `json:"iso3"`
+	Province      string      `json:"province"`
+	ExactCity     string      `json:"exactCity,omitempty"`
+	ExactProvince string      `json:"exactProvince,omitempty"`
+	StateANSI     string      `json:"state_ansi,omitempty"`
+	Timezone      *string     `json:"timezone"` // Null when unknown
+
+	AlternateNames  []AlternateName `json:"alt_names,omitempty"`
+	AreaKm2         float64         `json:"area_km2,omitempty"`
+	TimezoneWarning bool            `json:"timezone_warning,omitempty"`
+}
+
+// MarshalJSON encodes the city like the records of the city-timezones npm
+// package, so the raw dataset round-trips: an unknown timezone is null, the
+// numeric placeholder ISO2 code of disputed territories ("-99") is a number,
+// and the optional keys exactCity, exactProvince and state_ansi are omitted
+// when empty.
+func (c CityData) MarshalJSON() ([]byte, error) {
+	out := cityDataJSON{
+		City:          c.City,
+		CityASCII:     c.CityASCII,
+		Lat:           c.Lat,
+		Lng:           c.Lng,
+		Pop:           c.Pop,
+		Country:       c.Country,
+		ISO2:          c.ISO2,
+		ISO3:          c.ISO3,
+		Province:      c.Province,
+		ExactCity:     c.ExactCity,
+		ExactProvince: c.ExactProvince,
+		StateANSI:     c.StateANSI,
+
+		AlternateNames:  c.AlternateNames,
+		AreaKm2:         c.AreaKm2,
+		TimezoneWarning: c.TimezoneWarning,
+	}
+
+	if _, err := strconv.Atoi(c.ISO2); err == nil {
+		out.ISO2 = json.Number(c.ISO2)
+	}
+	if c.Timezone != "" {
+		out.Timezone = &c.Timezone
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a city in the format of MarshalJSON, accepting the
+// same variations as UnmarshalCityData such as numeric ISO codes
+func (c *CityData) UnmarshalJSON(data []byte) error {
+	var raw CityDataRaw
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = raw.ToCityData()
+	return nil
+}
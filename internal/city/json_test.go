@@ -0,0 +1,115 @@
+package city
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/richoandika/city-timezones-go/data"
+)
+
+func TestCityDataJSON(t *testing.T) {
+	t.Run("Upstream keys and order", func(t *testing.T) {
+		city := CityData{
+			City: "Chicago", CityASCII: "Chicago", Lat: 41.82999066, Lng: -87.75005497, Pop: 5915976,
+			Country: "United States of America", ISO2: "US", ISO3: "USA", Province: "Illinois",
+			StateANSI: "IL", Timezone: "America/Chicago",
+		}
+
+		out, err := json.Marshal(city)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		expected := `{"city":"Chicago","city_ascii":"Chicago","lat":41.82999066,"lng":-87.75005497,"pop":5915976,` +
+			`"country":"United States of America","iso2":"US","iso3":"USA","province":"Illinois",` +
+			`"state_ansi":"IL","timezone":"America/Chicago"}`
+		if string(out) != expected {
+			t.Errorf("Expected %s, got %s", expected, out)
+		}
+	})
+
+	t.Run("Null timezone and numeric ISO2", func(t *testing.T) {
+		record := `{"city":"Pristina","city_ascii":"Pristina","lat":42.66670961,"lng":21.16598425,"pop":331700,` +
+			`"country":"Kosovo","iso2":-99,"iso3":"KOS","province":"Pristina","timezone":null}`
+
+		var city CityData
+		if err := json.Unmarshal([]byte(record), &city); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if city.ISO2 != "-99" || city.Timezone != "" {
+			t.Errorf("Expected ISO2 -99 and no timezone, got %+v", city)
+		}
+
+		out, err := json.Marshal(city)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if string(out) != record {
+			t.Errorf("Expected %s, got %s", record, out)
+		}
+	})
+
+	t.Run("Supplemental fields", func(t *testing.T) {
+		city := CityData{
+			City:            "Munich",
+			Timezone:        "Europe/Berlin",
+			AlternateNames:  []AlternateName{{Name: "München", Lang: "de"}},
+			AreaKm2:         310.7,
+			TimezoneWarning: true,
+		}
+
+		out, err := json.Marshal(city)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		var decoded CityData
+		if err := json.Unmarshal(out, &decoded); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if !reflect.DeepEqual(decoded, city) {
+			t.Errorf("Expected %+v, got %+v", city, decoded)
+		}
+	})
+
+	t.Run("Bundled dataset round-trips", func(t *testing.T) {
+		zr, err := gzip.NewReader(bytes.NewReader(data.CityMap))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cityMap, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cities, err := UnmarshalCityData(cityMap)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		out, err := json.Marshal(cities)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		var original, roundTripped []map[string]interface{}
+		if err := json.Unmarshal(cityMap, &original); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(out, &roundTripped); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(roundTripped) != len(original) {
+			t.Fatalf("Expected %d records, got %d", len(original), len(roundTripped))
+		}
+		for i := range original {
+			if !reflect.DeepEqual(roundTripped[i], original[i]) {
+				t.Fatalf("Record %d differs:\n%v\n%v", i, original[i], roundTripped[i])
+			}
+		}
+	})
+}
@@ -1,11 +1,14 @@
 package city
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
-	"runtime"
+	"strings"
 	"sync"
+
+	"github.com/richoandika/city-timezones-go/data"
 )
 
 var (
@@ -14,45 +17,120 @@ var (
 	loadError error
 )
 
-// LoadCityData loads the city data from the JSON file
+// LoadCityData loads the embedded city data on first use
 func LoadCityData() ([]CityData, error) {
 	loadOnce.Do(func() {
-		cityData, loadError = loadCityDataFromFile()
+		cityData, loadError = loadBundledCityData()
 	})
 	return cityData, loadError
 }
 
-// loadCityDataFromFile loads city data from the data/cityMap.json file
-func loadCityDataFromFile() ([]CityData, error) {
-	// Get the path to the data file relative to this source file
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		return nil, fmt.Errorf("failed to get current file path")
+// loadBundledCityData decompresses and decodes the embedded dataset
+func loadBundledCityData() ([]CityData, error) {
+	// Decompress while decoding, so the uncompressed data is never held as a whole
+	zr, err := gzip.NewReader(bytes.NewReader(data.CityMap))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress city data: %w", err)
 	}
+	defer zr.Close()
 
-	// Navigate to the project root and find the data file
-	projectRoot := filepath.Join(filepath.Dir(filename), "..", "..")
-	dataPath := filepath.Join(projectRoot, "data", "cityMap.json")
+	cities, err := decodeCityData(zr, newStringInterner())
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal city data: %w", err)
+	}
 
-	// Verify the file exists and is readable
-	if _, err := os.Stat(dataPath); err != nil {
-		return nil, fmt.Errorf("city data file not found at %s: %w", dataPath, err)
+	if err := loadSupplementalData(cities); err != nil {
+		return nil, err
 	}
 
-	data, err := os.ReadFile(dataPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read city data file: %w", err)
+	return cities, nil
+}
+
+// supplementRef identifies the cities a supplemental data record applies to.
+// A record without a province applies to every city with that name in the country.
+type supplementRef struct {
+	City     string `json:"city"`
+	ISO2     string `json:"iso2"`
+	Province string `json:"province"`
+}
+
+// matches reports whether the city is identified by the reference
+func (ref supplementRef) matches(city CityData) bool {
+	if city.City != ref.City || !strings.EqualFold(city.ISO2, ref.ISO2) {
+		return false
 	}
+	return ref.Province == "" || city.Province == ref.Province
+}
 
-	cities, err := UnmarshalCityData(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal city data: %w", err)
+// alternateNamesEntry is a record of the supplemental data/alternateNames.json file
+type alternateNamesEntry struct {
+	supplementRef
+	Names []AlternateName `json:"alt_names"`
+}
+
+// cityAreaEntry is a record of the supplemental data/cityArea.json file
+type cityAreaEntry struct {
+	supplementRef
+	AreaKm2 float64 `json:"area_km2"`
+}
+
+// loadSupplementalData merges the supplemental data files into the loaded cities
+func loadSupplementalData(cities []CityData) error {
+	var names []alternateNamesEntry
+	if err := json.Unmarshal(data.AlternateNames, &names); err != nil {
+		return fmt.Errorf("failed to unmarshal supplemental data file alternateNames.json: %w", err)
 	}
+	applyAlternateNames(cities, names)
 
-	return cities, nil
+	var areas []cityAreaEntry
+	if err := json.Unmarshal(data.CityArea, &areas); err != nil {
+		return fmt.Errorf("failed to unmarshal supplemental data file cityArea.json: %w", err)
+	}
+	applyCityAreas(cities, areas)
+
+	return nil
+}
+
+// applyAlternateNames attaches each entry's names to the cities it
+// identifies, skipping names a city already has
+func applyAlternateNames(cities []CityData, entries []alternateNamesEntry) {
+	for _, entry := range entries {
+		for i := range cities {
+			if !entry.matches(cities[i]) {
+				continue
+			}
+			for _, name := range entry.Names {
+				if !hasAlternateName(cities[i], name) {
+					cities[i].AlternateNames = append(cities[i].AlternateNames, name)
+				}
+			}
+		}
+	}
+}
+
+// hasAlternateName reports whether the city already has the alternate name
+func hasAlternateName(city CityData, name AlternateName) bool {
+	for _, alt := range city.AlternateNames {
+		if alt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyCityAreas sets the area of the cities each entry identifies, unless
+// the dataset already provides one
+func applyCityAreas(cities []CityData, entries []cityAreaEntry) {
+	for _, entry := range entries {
+		for i := range cities {
+			if cities[i].AreaKm2 == 0 && entry.matches(cities[i]) {
+				cities[i].AreaKm2 = entry.AreaKm2
+			}
+		}
+	}
 }
 
-// GetCityData returns the loaded city data
+// GetCityData returns the cities of the default client's dataset
 func GetCityData() ([]CityData, error) {
-	return LoadCityData()
+	return defaultClient.Cities()
 }
@@ -2,6 +2,8 @@ package city
 
 import (
 	"testing"
+
+	"github.com/richoandika/city-timezones-go/data"
 )
 
 func TestLoadCityData(t *testing.T) {
@@ -35,6 +37,27 @@ func TestLoadCityData(t *testing.T) {
 	})
 }
 
+func TestLoadBundledCityData(t *testing.T) {
+	cities, err := loadBundledCityData()
+	if err != nil {
+		t.Fatalf("Should load data without error: %v", err)
+	}
+
+	if !data.Lite {
+		if len(cities) != 7326 {
+			t.Errorf("Expected 7326 cities, got %d", len(cities))
+		}
+		return
+	}
+
+	// The lite dataset only contains large cities
+	for _, city := range cities {
+		if city.Pop < data.LiteMinPopulation {
+			t.Errorf("Expected no city below %d inhabitants, got %s (%.0f)", data.LiteMinPopulation, city.City, city.Pop)
+		}
+	}
+}
+
 func TestGetCityData(t *testing.T) {
 	t.Run("Get city data", func(t *testing.T) {
 		cities, err := GetCityData()
@@ -0,0 +1,120 @@
+package city
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/richoandika/city-timezones-go/data"
+)
+
+// DatasetMetadata describes the dataset embedded in the binary, or the
+// dataset a client answers from
+type DatasetMetadata struct {
+	// Version identifies the data revision; it changes only when the data does
+	Version string `json:"version"`
+
+	// Commit is the repository commit the dataset was generated from, with a
+	// "-dirty" suffix for uncommitted changes
+	Commit string `json:"commit"`
+
+	// Cities is the number of embedded cities
+	Cities int `json:"cities"`
+
+	// Lite is set for binaries built with the citytz_lite tag, which embed only
+	// cities of at least LiteMinPopulation inhabitants
+	Lite              bool `json:"lite"`
+	LiteMinPopulation int  `json:"lite_min_population,omitempty"`
+
+	Source    string `json:"source"`     // Attribution of the data
+	SourceURL string `json:"source_url"` // Where the data comes from
+
+	// GeneratedAt is when the embedded dataset was generated
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// SHA256 and UpdateURL identify a dataset applied by an update. Only
+	// Cities is set besides them, since the embedded provenance no longer
+	// applies.
+	SHA256    string `json:"sha256,omitempty"`
+	UpdateURL string `json:"update_url,omitempty"`
+}
+
+var (
+	datasetMetadata     DatasetMetadata
+	datasetMetadataOnce sync.Once
+	datasetMetadataErr  error
+)
+
+// DatasetInfo returns the version and provenance of the default client's
+// dataset, e.g. to tell which data revision a deployed binary answers from.
+// This is the embedded dataset unless it was replaced, e.g. by an update.
+func DatasetInfo() (DatasetMetadata, error) {
+	return defaultClient.DatasetInfo()
+}
+
+// DatasetInfo returns the version and provenance of the dataset the client
+// answers from: the embedded metadata for the bundled dataset, the checksum,
+// URL and city count of an applied update, and only the city count for
+// custom datasets and providers.
+func (c *Client) DatasetInfo() (DatasetMetadata, error) {
+	if c.provider != nil {
+		n, err := c.Len()
+		return DatasetMetadata{Cities: n}, err
+	}
+
+	c.mu.RLock()
+	origin := c.origin
+	cities := -1
+	if c.data != nil {
+		cities = len(c.data.cities)
+	}
+	c.mu.RUnlock()
+
+	switch {
+	case origin.sha256 != "":
+		return DatasetMetadata{Cities: cities, SHA256: origin.sha256, UpdateURL: origin.url}, nil
+	case origin.custom:
+		return DatasetMetadata{Cities: cities}, nil
+	default:
+		return embeddedDatasetInfo()
+	}
+}
+
+// datasetOrigin records where a client's dataset came from
+type datasetOrigin struct {
+	custom bool   // Set for datasets given by the caller or changed by AddCities
+	sha256 string // Checksum of a dataset applied by an update
+	url    string // URL of a dataset applied by an update
+}
+
+// embeddedDatasetInfo returns the metadata of the embedded dataset
+func embeddedDatasetInfo() (DatasetMetadata, error) {
+	datasetMetadataOnce.Do(func() {
+		datasetMetadata, datasetMetadataErr = parseDatasetMetadata(data.MetadataJSON, data.Lite)
+	})
+	return datasetMetadata, datasetMetadataErr
+}
+
+// parseDatasetMetadata reads the generated metadata of the dataset variant
+func parseDatasetMetadata(raw []byte, lite bool) (DatasetMetadata, error) {
+	var generated data.Metadata
+	if err := json.Unmarshal(raw, &generated); err != nil {
+		return DatasetMetadata{}, NewDataLoadError("read dataset metadata", err)
+	}
+
+	metadata := DatasetMetadata{
+		Version:     generated.Version,
+		Commit:      generated.Commit,
+		Cities:      generated.Cities,
+		Source:      generated.Source,
+		SourceURL:   generated.SourceURL,
+		GeneratedAt: generated.GeneratedAt,
+	}
+	if lite {
+		metadata.Cities = generated.LiteCities
+		metadata.Lite = true
+		metadata.LiteMinPopulation = generated.LiteMinPopulation
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,70 @@
+package city
+
+import (
+	"testing"
+
+	"github.com/richoandika/city-timezones-go/data"
+)
+
+func TestDatasetInfo(t *testing.T) {
+	t.Run("Describes the embedded dataset", func(t *testing.T) {
+		info, err := DatasetInfo()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		if info.Version == "" || info.Source == "" || info.GeneratedAt.IsZero() {
+			t.Errorf("Expected version, source and generation date, got %+v", info)
+		}
+		if info.Lite != data.Lite {
+			t.Errorf("Expected Lite %v, got %v", data.Lite, info.Lite)
+		}
+
+		cities, err := loadBundledCityData()
+		if err != nil {
+			t.Fatalf("Should load data without error: %v", err)
+		}
+		if info.Cities != len(cities) {
+			t.Errorf("Expected %d cities, got %d", len(cities), info.Cities)
+		}
+	})
+
+	t.Run("Lite variant", func(t *testing.T) {
+		info, err := parseDatasetMetadata([]byte(`{"version": "abc", "cities": 10, "lite_cities": 4, "lite_min_population": 100000}`), true)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if !info.Lite || info.Cities != 4 || info.LiteMinPopulation != 100000 {
+			t.Errorf("Expected lite metadata, got %+v", info)
+		}
+	})
+
+	t.Run("Client datasets", func(t *testing.T) {
+		embedded, _ := DatasetInfo()
+
+		client := NewClient(ClientOptions{Cities: []CityData{{City: "Springfield"}}})
+		if info, _ := client.DatasetInfo(); info.Version != "" || info.Cities != 1 {
+			t.Errorf("Expected only the city count of a custom dataset, got %+v", info)
+		}
+
+		if err := client.Reload(); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if info, _ := client.DatasetInfo(); info.Version != embedded.Version {
+			t.Errorf("Expected the embedded metadata after Reload, got %+v", info)
+		}
+
+		if err := client.AddCity(CityData{City: "Shelbyville"}); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if info, _ := client.DatasetInfo(); info.Version != "" || info.Cities != embedded.Cities+1 {
+			t.Errorf("Expected a custom dataset after AddCity, got %+v", info)
+		}
+	})
+
+	t.Run("Invalid metadata", func(t *testing.T) {
+		if _, err := parseDatasetMetadata([]byte(`invalid`), false); err == nil {
+			t.Error("Should have error for invalid metadata")
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package city
+
+import (
+	"strings"
+)
+
+// LocalizedName returns the city's name in the given BCP 47 language tag.
+// A regional tag such as "de-AT" falls back to its base language "de", and
+// the dataset name is returned when no alternate name matches.
+func (c CityData) LocalizedName(lang string) string {
+	tag := normalizeLanguageTag(lang)
+	if tag == "" {
+		return c.City
+	}
+
+	base, _, _ := strings.Cut(tag, "-")
+	fallback := ""
+	for _, alt := range c.AlternateNames {
+		altTag := normalizeLanguageTag(alt.Lang)
+		if altTag == tag {
+			return alt.Name
+		}
+		if fallback == "" && altTag == base {
+			fallback = alt.Name
+		}
+	}
+
+	if fallback != "" {
+		return fallback
+	}
+	return c.City
+}
+
+// LocalizeCities returns a copy of cities with each name localized to lang
+func LocalizeCities(cities []CityData, lang string) []CityData {
+	localized := make([]CityData, len(cities))
+	for i, city := range cities {
+		city.City = city.LocalizedName(lang)
+		localized[i] = city
+	}
+	return localized
+}
+
+// normalizeLanguageTag lowercases a language tag and uses '-' as separator
+func normalizeLanguageTag(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
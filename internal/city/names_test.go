@@ -0,0 +1,123 @@
+package city
+
+import (
+	"testing"
+)
+
+func TestLocalizedName(t *testing.T) {
+	munich := CityData{
+		City: "Munich",
+		AlternateNames: []AlternateName{
+			{Name: "München", Lang: "de"},
+			{Name: "Munique", Lang: "pt"},
+			{Name: "Monaco di Baviera", Lang: "it"},
+		},
+	}
+
+	t.Run("Exact language tag", func(t *testing.T) {
+		if got := munich.LocalizedName("de"); got != "München" {
+			t.Errorf("Expected München, got %s", got)
+		}
+	})
+
+	t.Run("Regional tag falls back to base language", func(t *testing.T) {
+		if got := munich.LocalizedName("pt_BR"); got != "Munique" {
+			t.Errorf("Expected Munique, got %s", got)
+		}
+	})
+
+	t.Run("Unknown language keeps dataset name", func(t *testing.T) {
+		if got := munich.LocalizedName("fi"); got != "Munich" {
+			t.Errorf("Expected Munich, got %s", got)
+		}
+	})
+
+	t.Run("Empty language keeps dataset name", func(t *testing.T) {
+		if got := munich.LocalizedName(""); got != "Munich" {
+			t.Errorf("Expected Munich, got %s", got)
+		}
+	})
+}
+
+func TestLocalizeCities(t *testing.T) {
+	t.Run("Does not modify input", func(t *testing.T) {
+		cities := []CityData{{City: "Vienna", AlternateNames: []AlternateName{{Name: "Wien", Lang: "de"}}}}
+
+		localized := LocalizeCities(cities, "de")
+		if localized[0].City != "Wien" {
+			t.Errorf("Expected Wien, got %s", localized[0].City)
+		}
+		if cities[0].City != "Vienna" {
+			t.Errorf("Input should be unchanged, got %s", cities[0].City)
+		}
+	})
+}
+
+func TestAlternateNameLookups(t *testing.T) {
+	t.Run("LookupViaCity matches localized name", func(t *testing.T) {
+		cities, err := LookupViaCity("München")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].City != "Munich" {
+			t.Errorf("Expected Munich, got %v", cities)
+		}
+	})
+
+	t.Run("LookupViaCity matches Cyrillic name", func(t *testing.T) {
+		cities, err := LookupViaCity("Киев")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].City != "Kyiv" {
+			t.Errorf("Expected Kyiv, got %v", cities)
+		}
+	})
+
+	t.Run("LookupViaCity matches spelling variant", func(t *testing.T) {
+		cities, err := LookupViaCity("kiev")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].ISO2 != "UA" {
+			t.Errorf("Expected Kyiv, got %v", cities)
+		}
+	})
+
+	t.Run("SearchCities returns localized names", func(t *testing.T) {
+		options := DefaultSearchOptions()
+		options.ExactMatch = true
+		options.Language = "de"
+
+		cities, err := SearchCities("vienna", options)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) == 0 || cities[0].City != "Wien" {
+			t.Errorf("Expected Wien, got %v", cities)
+		}
+	})
+}
+
+func TestApplyAlternateNames(t *testing.T) {
+	t.Run("Province narrows matching cities", func(t *testing.T) {
+		cities := []CityData{
+			{City: "Florence", ISO2: "US", Province: "Alabama"},
+			{City: "Florence", ISO2: "US", Province: "South Carolina"},
+		}
+		entries := []alternateNamesEntry{
+			{
+				supplementRef: supplementRef{City: "Florence", ISO2: "us", Province: "Alabama"},
+				Names:         []AlternateName{{Name: "Florence AL"}},
+			},
+		}
+
+		applyAlternateNames(cities, entries)
+		if len(cities[0].AlternateNames) != 1 {
+			t.Errorf("Expected Alabama city to get the name, got %v", cities[0].AlternateNames)
+		}
+		if len(cities[1].AlternateNames) != 0 {
+			t.Errorf("Expected South Carolina city unchanged, got %v", cities[1].AlternateNames)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package city
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizationStage is a named step of a Client's normalization pipeline.
+// Queries and city fields both run through the pipeline before they are
+// compared, so stages must be deterministic.
+type NormalizationStage struct {
+	Name  string
+	Apply func(string) string
+
+	// Config holds the table of the configurable built-in stages, the
+	// synonyms of ExpandSynonymsStage and the runes of TransliterateStage,
+	// so ConfigSnapshot can record them. Changing it does not change Apply.
+	Config map[string]string
+
+	builtin bool // Set by the constructors of the built-in stages
+}
+
+// Names of the built-in normalization stages
+const (
+	StageLowercase        = "lowercase"
+	StageFoldDiacritics   = "fold_diacritics"
+	StageStripPunctuation = "strip_punctuation"
+	StageExpandSynonyms   = "expand_synonyms"
+	StageTransliterate    = "transliterate"
+)
+
+// DefaultNormalization returns the default pipeline, which only lowercases
+func DefaultNormalization() []NormalizationStage {
+	return []NormalizationStage{LowercaseStage()}
+}
+
+// LowercaseStage lowercases the input
+func LowercaseStage() NormalizationStage {
+	return NormalizationStage{Name: StageLowercase, Apply: strings.ToLower, builtin: true}
+}
+
+// FoldDiacriticsStage replaces accented Latin letters with their ASCII base
+// letters, e.g. "Zürich" becomes "Zurich" and "Kraków" becomes "Krakow"
+func FoldDiacriticsStage() NormalizationStage {
+	return NormalizationStage{Name: StageFoldDiacritics, Apply: foldDiacritics, builtin: true}
+}
+
+// StripPunctuationStage removes apostrophes, replaces other punctuation with
+// spaces and collapses whitespace, e.g. "Winston-Salem" becomes "Winston Salem"
+func StripPunctuationStage() NormalizationStage {
+	return NormalizationStage{Name: StageStripPunctuation, Apply: stripPunctuation, builtin: true}
+}
+
+// ExpandSynonymsStage replaces whole words found in synonyms with their
+// expansion, e.g. {"st": "saint"}. Words are separated by whitespace, so the
+// stage usually follows LowercaseStage and StripPunctuationStage.
+func ExpandSynonymsStage(synonyms map[string]string) NormalizationStage {
+	table := make(map[string]string, len(synonyms))
+	config := make(map[string]string, len(synonyms))
+	for word, expansion := range synonyms {
+		table[word] = expansion
+		config[word] = expansion
+	}
+
+	return NormalizationStage{
+		Name:    StageExpandSynonyms,
+		Config:  config,
+		builtin: true,
+		Apply: func(input string) string {
+			words := strings.Fields(input)
+			for i, word := range words {
+				if expansion, ok := table[word]; ok {
+					words[i] = expansion
+				}
+			}
+			return strings.Join(words, " ")
+		},
+	}
+}
+
+// TransliterateStage replaces each rune found in table with its
+// transliteration, e.g. CyrillicTransliteration maps "киев" to "kiev"
+func TransliterateStage(table map[rune]string) NormalizationStage {
+	runes := make(map[rune]string, len(table))
+	config := make(map[string]string, len(table))
+	for r, replacement := range table {
+		runes[r] = replacement
+		config[string(r)] = replacement
+	}
+
+	return NormalizationStage{
+		Name:    StageTransliterate,
+		Config:  config,
+		builtin: true,
+		Apply: func(input string) string {
+			return replaceRunes(input, runes)
+		},
+	}
+}
+
+// CommonSynonyms are abbreviations frequently used in city names
+var CommonSynonyms = map[string]string{
+	"st":  "saint",
+	"ste": "sainte",
+	"ft":  "fort",
+	"mt":  "mount",
+	"pt":  "port",
+}
+
+// CyrillicTransliteration is a simple lowercase Russian/Ukrainian
+// transliteration table for use with TransliterateStage
+var CyrillicTransliteration = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e",
+	'є': "ye", 'ё': "yo", 'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p",
+	'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e",
+	'ю': "yu", 'я': "ya",
+}
+
+// normalize runs the input through the pipeline stages in order
+func normalize(pipeline []NormalizationStage, input string) string {
+	for _, stage := range pipeline {
+		input = stage.Apply(input)
+	}
+	return input
+}
+
+// Single-rune diacritic folds: each rune of diacriticRunes maps to the rune
+// at the same position of diacriticBases
+const (
+	diacriticRunes = "ÀÁÂÃÄÅÇÈÉÊËÌÍÎÏÐÑÒÓÔÕÖØÙÚÛÜÝàáâãäåçèéêëìíîïðñòóôõöøùúûüýÿĀāĂăĄąĆćĈĉĊċČčĎďĐđĒēĔĕĖėĘęĚěĜĝĞğĠġĢģĤĥĦħĨĩĪīĬĭĮįİıĴĵĶķĹĺĻļĽľŁłŃńŅņŇňŌōŎŏŐőŔŕŖŗŘřŚśŜŝŞşŠšŢţŤťŦŧŨũŪūŬŭŮůŰűŲųŴŵŶŷŸŹźŻżŽžſƠơƯưǍǎǏǐǑǒǓǔǕǖǗǘǙǚǛǜǞǟǠǡǦǧǨǩǪǫǬǭǰǴǵǸǹǺǻȀȁȂȃȄȅȆȇȈȉȊȋȌȍȎȏȐȑȒȓȔȕȖȗȘșȚțȞȟȦȧȨȩȪȫȬȭȮȯȰȱȲȳ"
+	diacriticBases = "AAAAAACEEEEIIIIDNOOOOOOUUUUYaaaaaaceeeeiiiidnoooooouuuuyyAaAaAaCcCcCcCcDdDdEeEeEeEeEeGgGgGgGgHhHhIiIiIiIiIiJjKkLlLlLlLlNnNnNnOoOoOoRrRrRrSsSsSsSsTtTtTtUuUuUuUuUuUuWwYyYZzZzZzsOoUuAaIiOoUuUuUuUuUuAaAaGgKkOoOojGgNnAaAaAaEeEeIiIiOoOoRrRrUuUuSsTtHhAaEeOoOoOoOoYy"
+)
+
+// diacriticFolds maps accented Latin letters to their ASCII base letters
+var diacriticFolds = buildDiacriticFolds()
+
+func buildDiacriticFolds() map[rune]string {
+	folds := map[rune]string{
+		'Æ': "AE", 'æ': "ae", 'Œ': "OE", 'œ': "oe", 'ß': "ss", 'ẞ': "SS",
+		'Þ': "TH", 'þ': "th", 'Ĳ': "IJ", 'ĳ': "ij",
+	}
+
+	bases := []rune(diacriticBases)
+	for i, r := range []rune(diacriticRunes) {
+		folds[r] = string(bases[i])
+	}
+
+	return folds
+}
+
+// foldDiacritics replaces accented Latin letters with their ASCII base letters
+func foldDiacritics(input string) string {
+	return replaceRunes(input, diacriticFolds)
+}
+
+// replaceRunes replaces each rune found in table, leaving the input untouched
+// when no rune needs replacing
+func replaceRunes(input string, table map[rune]string) string {
+	needsReplacing := false
+	for _, r := range input {
+		if _, ok := table[r]; ok {
+			needsReplacing = true
+			break
+		}
+	}
+	if !needsReplacing {
+		return input
+	}
+
+	var b strings.Builder
+	b.Grow(len(input))
+	for _, r := range input {
+		if replacement, ok := table[r]; ok {
+			b.WriteString(replacement)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// stripPunctuation removes apostrophes, replaces other punctuation with spaces
+// and collapses whitespace
+func stripPunctuation(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+	for _, r := range input {
+		switch {
+		case r == '\'' || r == '’' || r == 'ʼ':
+			// Drop apostrophes so "Dnipropetrovs'k" matches "Dnipropetrovsk"
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,61 @@
+package city
+
+import (
+	"testing"
+)
+
+func TestNormalizationStages(t *testing.T) {
+	tests := []struct {
+		name     string
+		stage    NormalizationStage
+		input    string
+		expected string
+	}{
+		{"Lowercase", LowercaseStage(), "New York", "new york"},
+		{"Fold diacritics", FoldDiacriticsStage(), "Zürich Kraków São Paulo", "Zurich Krakow Sao Paulo"},
+		{"Fold ligatures", FoldDiacriticsStage(), "Straße Æro", "Strasse AEro"},
+		{"Fold leaves other scripts", FoldDiacriticsStage(), "Киев", "Киев"},
+		{"Strip punctuation", StripPunctuationStage(), "Winston-Salem,  N.C.", "Winston Salem N C"},
+		{"Strip apostrophes", StripPunctuationStage(), "Dnipropetrovs'k", "Dnipropetrovsk"},
+		{"Expand synonyms", ExpandSynonymsStage(CommonSynonyms), "st petersburg", "saint petersburg"},
+		{"Expand whole words only", ExpandSynonymsStage(CommonSynonyms), "stockholm", "stockholm"},
+		{"Transliterate", TransliterateStage(CyrillicTransliteration), "киев", "kiev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stage.Apply(tt.input); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Run("Stages run in order", func(t *testing.T) {
+		pipeline := []NormalizationStage{
+			LowercaseStage(),
+			StripPunctuationStage(),
+			ExpandSynonymsStage(CommonSynonyms),
+		}
+
+		if got := normalize(pipeline, "St. Petersburg"); got != "saint petersburg" {
+			t.Errorf("Expected saint petersburg, got %q", got)
+		}
+	})
+
+	t.Run("Empty pipeline", func(t *testing.T) {
+		if got := normalize(nil, "Chicago"); got != "Chicago" {
+			t.Errorf("Expected Chicago, got %q", got)
+		}
+	})
+
+	t.Run("Diacritic tables are aligned", func(t *testing.T) {
+		if len([]rune(diacriticRunes)) != len([]rune(diacriticBases)) {
+			t.Fatalf("diacriticRunes and diacriticBases differ in length")
+		}
+		if got := foldDiacritics("ÀÿȳŁ"); got != "AyyL" {
+			t.Errorf("Expected AyyL, got %q", got)
+		}
+	})
+}
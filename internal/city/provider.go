@@ -0,0 +1,187 @@
+package city
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// DataProvider serves the cities a Client searches. The embedded dataset is
+// the default; set ClientOptions.Provider to serve cities from elsewhere,
+// e.g. a database. Implementations must be safe for concurrent use.
+//
+// Clients validate and trim queries before passing them on. Scanning searches
+// such as FindFromCityStateProvince and SearchCities filter AllCities, which
+// clients load once until RefreshProvider, the other lookups use the indexed
+// methods.
+type DataProvider interface {
+	// AllCities returns every city
+	AllCities(ctx context.Context) ([]CityData, error)
+
+	// ByName returns the cities whose name or alternate name matches name.
+	// The embedded provider compares names after the client's normalization.
+	ByName(ctx context.Context, name string) ([]CityData, error)
+
+	// ByISO returns the cities of the country with the uppercase ISO2 or
+	// ISO3 code
+	ByISO(ctx context.Context, code string) ([]CityData, error)
+
+	// ByTimezone returns the cities in the IANA timezone, compared
+	// case-insensitively, most populous first
+	ByTimezone(ctx context.Context, timezone string) ([]CityData, error)
+
+	// Nearest returns up to limit cities, limit being at least 1, ordered by
+	// their great-circle distance to the coordinates, nearest first
+	Nearest(ctx context.Context, lat, lng float64, limit int) ([]CityData, error)
+}
+
+// NameNormalizer is implemented by providers that normalize the names passed
+// to ByName with a pipeline of their own. Clients cache ByName results under
+// the provider's normalization, so names only share results if the provider
+// matches them alike, and adopt it when ClientOptions.Normalization is nil.
+// Without it, ByName results are cached under the trimmed name.
+type NameNormalizer interface {
+	// Normalization returns the pipeline the provider compares names with
+	Normalization() []NormalizationStage
+}
+
+// NewDatasetProvider returns a provider serving cities from memory, like a
+// client's embedded dataset, with the default normalization. Nil cities use
+// the bundled dataset.
+func NewDatasetProvider(cities []CityData) DataProvider {
+	return datasetProvider{NewClient(ClientOptions{Cities: cities})}
+}
+
+// datasetProvider serves a client's own dataset through its indexes
+type datasetProvider struct {
+	c *Client
+}
+
+// source returns the provider the client's lookups read from
+func (c *Client) source() DataProvider {
+	if c.provider != nil {
+		return c.provider
+	}
+	return datasetProvider{c}
+}
+
+// nameKey returns the key the results of ByName for name are cached under
+func (c *Client) nameKey(pipeline []NormalizationStage, name string) string {
+	if c.provider == nil {
+		return "city:" + normalize(pipeline, name)
+	}
+	if normalizer, ok := c.provider.(NameNormalizer); ok {
+		return "city:" + normalize(normalizer.Normalization(), name)
+	}
+	return "city:" + name
+}
+
+// allCities returns the cities the scanning searches filter. A provider's
+// cities are loaded once and kept outside the search cache until
+// RefreshProvider or a normalization change.
+func (c *Client) allCities(ctx context.Context) ([]CityData, error) {
+	if c.provider == nil {
+		return c.source().AllCities(ctx)
+	}
+
+	c.mu.RLock()
+	cities, generation := c.providerCities, c.generation
+	loaded := cities != nil && c.providerGeneration == generation
+	c.mu.RUnlock()
+	if loaded {
+		return cities, nil
+	}
+
+	cities, err := c.provider.AllCities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cities == nil {
+		cities = []CityData{}
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.providerCities, c.providerGeneration = cities, generation
+	}
+	c.mu.Unlock()
+	return cities, nil
+}
+
+// RefreshProvider discards the provider's cities the client keeps for
+// scanning searches and clears the cache, e.g. after the provider's data
+// changed. Clients without a provider only clear the cache.
+func (c *Client) RefreshProvider() {
+	c.mu.Lock()
+	c.providerCities = nil
+	c.generation++
+	c.mu.Unlock()
+
+	c.cache.Clear()
+}
+
+func (p datasetProvider) AllCities(_ context.Context) ([]CityData, error) {
+	var cities []CityData
+	err := p.c.view(func(d *dataset, _ []NormalizationStage) {
+		cities = d.all()
+	})
+	return cities, err
+}
+
+func (p datasetProvider) ByName(_ context.Context, name string) ([]CityData, error) {
+	var cities []CityData
+	err := p.c.view(func(d *dataset, pipeline []NormalizationStage) {
+		cities = d.citiesAt(d.byName[normalize(pipeline, name)])
+	})
+	return cities, err
+}
+
+func (p datasetProvider) ByISO(_ context.Context, code string) ([]CityData, error) {
+	var cities []CityData
+	err := p.c.view(func(d *dataset, _ []NormalizationStage) {
+		cities = d.citiesAt(d.byISO[strings.ToUpper(code)])
+	})
+	return cities, err
+}
+
+func (p datasetProvider) ByTimezone(_ context.Context, timezone string) ([]CityData, error) {
+	var cities []CityData
+	err := p.c.view(func(d *dataset, _ []NormalizationStage) {
+		cities = d.citiesAt(d.byTimezone[strings.ToLower(timezone)])
+	})
+	return cities, err
+}
+
+func (p datasetProvider) Nearest(_ context.Context, lat, lng float64, limit int) ([]CityData, error) {
+	var cities []CityData
+	err := p.c.view(func(d *dataset, _ []NormalizationStage) {
+		cities = nearestCities(d.cities, lat, lng, limit)
+	})
+	return cities, err
+}
+
+// nearestCities returns up to limit cities ordered by their distance to the
+// coordinates, preferring dataset order on ties
+func nearestCities(cities []CityData, lat, lng float64, limit int) []CityData {
+	type candidate struct {
+		index    int
+		distance float64
+	}
+
+	candidates := make([]candidate, len(cities))
+	for i, city := range cities {
+		candidates[i] = candidate{i, haversineKm(lat, lng, city.Lat, city.Lng)}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	if limit > len(candidates) {
+		limit = len(candidates)
+	}
+	nearest := make([]CityData, limit)
+	for i := range nearest {
+		nearest[i] = cities[candidates[i].index]
+	}
+	return nearest
+}
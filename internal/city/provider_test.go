@@ -0,0 +1,347 @@
+package city
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+var providerCities = []CityData{
+	{City: "Chicago", Lat: 41.83, Lng: -87.75, ISO2: "US", ISO3: "USA", Province: "Illinois", Timezone: "America/Chicago"},
+	{City: "Milwaukee", Lat: 43.05, Lng: -87.95, ISO2: "US", ISO3: "USA", Province: "Wisconsin", Timezone: "America/Chicago"},
+	{City: "München", Lat: 48.13, Lng: 11.57, ISO2: "DE", ISO3: "DEU", Province: "Bayern", Timezone: "Europe/Berlin"},
+	{City: "Auckland", Lat: -36.85, Lng: 174.76, ISO2: "NZ", ISO3: "NZL", Timezone: "Pacific/Auckland"},
+	{City: "Suva", Lat: -18.13, Lng: 178.44, ISO2: "FJ", ISO3: "FJI", Timezone: "Pacific/Fiji"},
+}
+
+// recordingProvider serves fixed cities and records the calls it receives
+type recordingProvider struct {
+	mu     sync.Mutex
+	calls  []string
+	ctxKey interface{}
+	caller interface{}
+	err    error
+}
+
+func (p *recordingProvider) record(ctx context.Context, call string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, call)
+	p.ctxKey = ctx.Value(hooksContextKey{})
+	p.caller = ctx.Value(callerContextKey{})
+	return p.err
+}
+
+func (p *recordingProvider) lastCall() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.calls) == 0 {
+		return ""
+	}
+	return p.calls[len(p.calls)-1]
+}
+
+func (p *recordingProvider) AllCities(ctx context.Context) ([]CityData, error) {
+	return providerCities, p.record(ctx, "AllCities")
+}
+
+func (p *recordingProvider) ByName(ctx context.Context, name string) ([]CityData, error) {
+	return providerCities[:1], p.record(ctx, "ByName "+name)
+}
+
+func (p *recordingProvider) ByISO(ctx context.Context, code string) ([]CityData, error) {
+	return providerCities[:2], p.record(ctx, "ByISO "+code)
+}
+
+func (p *recordingProvider) ByTimezone(ctx context.Context, timezone string) ([]CityData, error) {
+	return providerCities[:2], p.record(ctx, "ByTimezone "+timezone)
+}
+
+func (p *recordingProvider) Nearest(ctx context.Context, _, _ float64, limit int) ([]CityData, error) {
+	return providerCities[:limit], p.record(ctx, "Nearest")
+}
+
+// normalizingProvider is a recordingProvider comparing names with its own
+// pipeline
+type normalizingProvider struct {
+	recordingProvider
+	pipeline []NormalizationStage
+}
+
+func (p *normalizingProvider) Normalization() []NormalizationStage {
+	return p.pipeline
+}
+
+func (p *recordingProvider) countCalls(call string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	count := 0
+	for _, c := range p.calls {
+		if c == call {
+			count++
+		}
+	}
+	return count
+}
+
+func TestClientProvider(t *testing.T) {
+	provider := &recordingProvider{}
+	client := NewClient(ClientOptions{Provider: provider, CacheSize: 10})
+
+	t.Run("Lookups use the provider", func(t *testing.T) {
+		tests := []struct {
+			call    string
+			lookup  func() ([]CityData, error)
+			results int
+		}{
+			{"ByName Chicago", func() ([]CityData, error) { return client.LookupViaCity("  Chicago ") }, 1},
+			{"ByISO US", func() ([]CityData, error) { return client.FindFromIsoCode("us") }, 2},
+			{"ByTimezone America/Chicago", func() ([]CityData, error) { return client.FindFromTimezone("America/Chicago") }, 2},
+			{"Nearest", func() ([]CityData, error) { return client.NearestCities(42, -88, 2) }, 2},
+			{"AllCities", func() ([]CityData, error) { return client.SearchCities("bayern", DefaultSearchOptions()) }, 1},
+			{"AllCities", func() ([]CityData, error) { return client.FindFromCityStateProvince("wisconsin") }, 1},
+			{"ByName Chicago", func() ([]CityData, error) {
+				return client.FindCities(CityQuery{City: "Chicago", Province: "Illinois"})
+			}, 1},
+			{"AllCities", client.Cities, len(providerCities)},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.call, func(t *testing.T) {
+				cities, err := tt.lookup()
+				if err != nil {
+					t.Fatalf("Should not error: %v", err)
+				}
+				if call := provider.lastCall(); call != tt.call {
+					t.Errorf("Expected call %q, got %q", tt.call, call)
+				}
+				if len(cities) != tt.results {
+					t.Errorf("Expected %d results, got %d", tt.results, len(cities))
+				}
+			})
+		}
+	})
+
+	t.Run("Cached lookups skip the provider", func(t *testing.T) {
+		provider.mu.Lock()
+		before := len(provider.calls)
+		provider.mu.Unlock()
+
+		_, _ = client.LookupViaCity("Chicago")
+
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+		if len(provider.calls) != before {
+			t.Errorf("Expected a cache hit, got calls %v", provider.calls[before:])
+		}
+	})
+
+	t.Run("Provider cities are cached", func(t *testing.T) {
+		provider := &recordingProvider{}
+		client := NewClient(ClientOptions{Provider: provider, CacheSize: 10})
+
+		_, _ = client.SearchCities("bayern", DefaultSearchOptions())
+		_, _ = client.FindFromCityStateProvince("wisconsin")
+		_, _ = client.FindCities(CityQuery{Province: "Illinois"})
+		if n, _ := client.Len(); n != len(providerCities) {
+			t.Errorf("Expected %d cities, got %d", len(providerCities), n)
+		}
+		_, _ = client.DatasetInfo()
+		if calls := provider.countCalls("AllCities"); calls != 1 {
+			t.Errorf("Expected 1 AllCities call, got %d", calls)
+		}
+		if size := client.Cache().Size(); size != 0 {
+			t.Errorf("Expected the cities kept outside the search cache, got %d entries", size)
+		}
+
+		client.Cache().Clear()
+		_, _ = client.SearchCities("bayern", DefaultSearchOptions())
+		if calls := provider.countCalls("AllCities"); calls != 1 {
+			t.Errorf("Expected clearing the cache to keep the cities, got %d calls", calls)
+		}
+
+		client.RefreshProvider()
+		_, _ = client.SearchCities("bayern", DefaultSearchOptions())
+		if calls := provider.countCalls("AllCities"); calls != 2 {
+			t.Errorf("Expected the cities reloaded after RefreshProvider, got %d calls", calls)
+		}
+	})
+
+	t.Run("Names are cached under the provider's normalization", func(t *testing.T) {
+		provider := &normalizingProvider{pipeline: []NormalizationStage{LowercaseStage()}}
+		client := NewClient(ClientOptions{Provider: provider, CacheSize: 10})
+		if stages := client.Normalization(); len(stages) != 1 || stages[0].Name != StageLowercase {
+			t.Errorf("Expected the provider's normalization, got %d stages", len(stages))
+		}
+
+		// The default pipeline folds diacritics, so a client using it must
+		// not answer Zürich from the results for Zurich
+		client = NewClient(ClientOptions{Provider: provider, CacheSize: 10, Normalization: DefaultNormalization()})
+		_, _ = client.LookupViaCity("Zurich")
+		_, _ = client.LookupViaCity("ZURICH")
+		_, _ = client.LookupViaCity("Zürich")
+		calls := provider.countCalls("ByName Zurich") + provider.countCalls("ByName ZURICH") + provider.countCalls("ByName Zürich")
+		if calls != 2 {
+			t.Errorf("Expected 2 ByName calls, got %v", provider.calls)
+		}
+	})
+
+	t.Run("Names are cached as-is without a normalizer", func(t *testing.T) {
+		provider := &recordingProvider{}
+		client := NewClient(ClientOptions{Provider: provider, CacheSize: 10})
+		_, _ = client.LookupViaCity("Zurich")
+		_, _ = client.LookupViaCity("Zürich")
+		if calls := provider.countCalls("ByName Zurich") + provider.countCalls("ByName Zürich"); calls != 2 {
+			t.Errorf("Expected 2 ByName calls, got %v", provider.calls)
+		}
+	})
+
+	t.Run("Hook context is passed on", func(t *testing.T) {
+		client := NewClient(ClientOptions{Provider: provider, Hooks: &recordingHooks{}})
+		_, _ = client.FindFromIsoCode("US")
+
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+		if provider.ctxKey != "FindFromIsoCode" {
+			t.Errorf("Expected the context returned by OnLookupStart, got %v", provider.ctxKey)
+		}
+	})
+
+	t.Run("Dataset changes are rejected", func(t *testing.T) {
+		client := NewClient(ClientOptions{Provider: &recordingProvider{}})
+
+		if err := client.AddCity(CityData{City: "Springfield"}); !errors.Is(err, ErrProviderManaged) {
+			t.Errorf("AddCity: expected ErrProviderManaged, got %v", err)
+		}
+		if err := client.SetCities(nil); !errors.Is(err, ErrProviderManaged) {
+			t.Errorf("SetCities: expected ErrProviderManaged, got %v", err)
+		}
+		if err := client.Reload(); !errors.Is(err, ErrProviderManaged) {
+			t.Errorf("Reload: expected ErrProviderManaged, got %v", err)
+		}
+		options := DefaultUpdateOptions()
+		options.InsecureSkipVerify = true
+		if _, err := client.UpdateFromURL(context.Background(), "http://127.0.0.1:0/cities.json", options); !errors.Is(err, ErrProviderManaged) {
+			t.Errorf("UpdateFromURL: expected ErrProviderManaged, got %v", err)
+		}
+		if err := client.Watch(context.Background(), "http://127.0.0.1:0/cities.json", WatchOptions{UpdateOptions: options}); !errors.Is(err, ErrProviderManaged) {
+			t.Errorf("Watch: expected ErrProviderManaged, got %v", err)
+		}
+	})
+
+	t.Run("Provider errors", func(t *testing.T) {
+		failing := &recordingProvider{err: errors.New("connection refused")}
+		client := NewClient(ClientOptions{Provider: failing})
+
+		if _, err := client.LookupViaCity("Chicago"); err == nil {
+			t.Error("Expected the provider's error")
+		}
+		if _, err := client.Len(); err == nil {
+			t.Error("Expected the provider's error")
+		}
+	})
+}
+
+func TestDatasetProvider(t *testing.T) {
+	ctx := context.Background()
+	provider := NewDatasetProvider(providerCities)
+
+	t.Run("ByName", func(t *testing.T) {
+		cities, err := provider.ByName(ctx, "MÜNCHEN")
+		if err != nil || len(cities) != 1 || cities[0].City != "München" {
+			t.Errorf("Expected München, got %v, %v", cities, err)
+		}
+	})
+
+	t.Run("ByISO", func(t *testing.T) {
+		cities, err := provider.ByISO(ctx, "USA")
+		if err != nil || len(cities) != 2 {
+			t.Errorf("Expected 2 cities, got %d, %v", len(cities), err)
+		}
+	})
+
+	t.Run("ByTimezone", func(t *testing.T) {
+		cities, err := provider.ByTimezone(ctx, "america/chicago")
+		if err != nil || len(cities) != 2 {
+			t.Errorf("Expected 2 cities, got %d, %v", len(cities), err)
+		}
+	})
+
+	t.Run("Nearest", func(t *testing.T) {
+		cities, err := provider.Nearest(ctx, 43, -88, 2)
+		if err != nil || len(cities) != 2 || cities[0].City != "Milwaukee" || cities[1].City != "Chicago" {
+			t.Errorf("Expected Milwaukee and Chicago, got %v, %v", cities, err)
+		}
+
+		// Distances wrap around the antimeridian
+		cities, _ = provider.Nearest(ctx, -18, -179.5, 1)
+		if len(cities) != 1 || cities[0].City != "Suva" {
+			t.Errorf("Expected Suva, got %v", cities)
+		}
+
+		// Limits beyond the dataset return every city
+		if cities, _ := provider.Nearest(ctx, 0, 0, 100); len(cities) != len(providerCities) {
+			t.Errorf("Expected %d cities, got %d", len(providerCities), len(cities))
+		}
+	})
+}
+
+func TestNearestCities(t *testing.T) {
+	t.Run("Bundled dataset", func(t *testing.T) {
+		cities, err := NearestCities(41.88, -87.63, 3)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 3 || cities[0].City != "Chicago" {
+			t.Errorf("Expected Chicago first, got %v", cities)
+		}
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		if _, err := NearestCities(91, 0, 1); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput for the latitude, got %v", err)
+		}
+		if _, err := NearestCities(0, -181, 1); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput for the longitude, got %v", err)
+		}
+		if _, err := NearestCities(0, 0, 0); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput for the limit, got %v", err)
+		}
+	})
+}
+
+func TestFindFromTimezone(t *testing.T) {
+	cities, err := FindFromTimezone("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Should not error: %v", err)
+	}
+	if len(cities) == 0 {
+		t.Fatal("Expected cities in Europe/Berlin")
+	}
+	for _, city := range cities {
+		if city.Timezone != "Europe/Berlin" {
+			t.Errorf("Expected Europe/Berlin, got %s for %s", city.Timezone, city.City)
+		}
+	}
+
+	if cities, _ := FindFromTimezone("Mars/Olympus_Mons"); len(cities) != 0 {
+		t.Errorf("Expected no cities, got %d", len(cities))
+	}
+}
+
+func TestCacheResultGeneration(t *testing.T) {
+	client := NewClient(ClientOptions{Cities: providerCities, CacheSize: 10})
+
+	// A lookup that read the dataset before it changed must not cache its result
+	_, generation := client.snapshot()
+	client.SetCities([]CityData{{City: "Springfield"}})
+	client.cacheResult("city:chicago", providerCities[:1], generation)
+
+	if _, ok := client.cache.Get("city:chicago"); ok {
+		t.Error("Expected the stale result not to be cached")
+	}
+	if cities, _ := client.LookupViaCity("Chicago"); len(cities) != 0 {
+		t.Errorf("Expected no results from the new dataset, got %v", cities)
+	}
+}
@@ -0,0 +1,126 @@
+package city
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CityQuery describes a structured city search. Empty fields are ignored and
+// all non-empty fields must match (AND semantics).
+type CityQuery struct {
+	City     string // City name or alternate name
+	Province string // Province/state name or ANSI state code (e.g. "Missouri" or "MO")
+	ISO2     string // ISO2 or ISO3 country code
+}
+
+// FindCities searches for cities matching every non-empty field of the query.
+// City and province are compared after normalization and must match exactly.
+func (c *Client) FindCities(query CityQuery) ([]CityData, error) {
+	return c.FindCitiesContext(context.Background(), query)
+}
+
+// FindCitiesContext is like FindCities but passes ctx to the client's hooks and
+// provider
+func (c *Client) FindCitiesContext(ctx context.Context, query CityQuery) ([]CityData, error) {
+	trace := c.startLookup(ctx, "FindCities", fmt.Sprintf("%+v", query))
+	results, err := c.findCities(trace.ctx, query)
+	trace.end(len(results), false, err)
+	return results, err
+}
+
+// findCities implements FindCities
+func (c *Client) findCities(ctx context.Context, query CityQuery) ([]CityData, error) {
+	cityName, err := ValidateSearchInput(query.City, 100)
+	if err != nil {
+		return nil, fmt.Errorf("invalid city: %w", err)
+	}
+
+	province, err := ValidateSearchInput(query.Province, 100)
+	if err != nil {
+		return nil, fmt.Errorf("invalid province: %w", err)
+	}
+
+	isoCode, err := ValidateISOCode(query.ISO2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ISO code: %w", err)
+	}
+
+	if cityName == "" && province == "" && isoCode == "" {
+		if err := c.emptyInputError("query"); err != nil {
+			return nil, err
+		}
+		return []CityData{}, nil
+	}
+
+	// Narrow the candidates with an index where possible
+	var candidates []CityData
+	switch {
+	case cityName != "":
+		candidates, err = c.source().ByName(ctx, cityName)
+	case isoCode != "":
+		candidates, err = c.source().ByISO(ctx, isoCode)
+	default:
+		candidates, err = c.allCities(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := c.currentPipeline()
+	province = normalize(pipeline, province)
+
+	var results []CityData
+	for _, city := range candidates {
+		if province != "" && normalize(pipeline, city.Province) != province && normalize(pipeline, city.StateANSI) != province {
+			continue
+		}
+		if isoCode != "" && !strings.EqualFold(city.ISO2, isoCode) && !strings.EqualFold(city.ISO3, isoCode) {
+			continue
+		}
+		results = append(results, city)
+	}
+
+	if err := c.notFoundError(fmt.Sprintf("%+v", query), "structured query", len(results)); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ListProvinces returns the sorted, distinct province names of a country
+// identified by its ISO2 or ISO3 code
+func (c *Client) ListProvinces(isoCode string) ([]string, error) {
+	return c.ListProvincesContext(context.Background(), isoCode)
+}
+
+// ListProvincesContext is like ListProvinces but passes ctx to the client's
+// hooks and provider
+func (c *Client) ListProvincesContext(ctx context.Context, isoCode string) ([]string, error) {
+	trace := c.startLookup(ctx, "ListProvinces", isoCode)
+	provinces, err := c.listProvinces(trace.ctx, isoCode)
+	trace.end(len(provinces), false, err)
+	return provinces, err
+}
+
+// listProvinces implements ListProvinces
+func (c *Client) listProvinces(ctx context.Context, isoCode string) ([]string, error) {
+	cities, err := c.findFromIsoCode(ctx, isoCode)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	provinces := []string{}
+	for _, city := range cities {
+		if city.Province == "" || seen[city.Province] {
+			continue
+		}
+		seen[city.Province] = true
+		provinces = append(provinces, city.Province)
+	}
+
+	sort.Strings(provinces)
+	return provinces, nil
+}
@@ -0,0 +1,111 @@
+package city
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestFindCities(t *testing.T) {
+	t.Run("Springfield in Missouri", func(t *testing.T) {
+		cities, err := FindCities(CityQuery{City: "Springfield", Province: "Missouri"})
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].Province != "Missouri" {
+			t.Errorf("Expected Springfield, Missouri, got %v", cities)
+		}
+	})
+
+	t.Run("Province by state code", func(t *testing.T) {
+		cities, err := FindCities(CityQuery{City: "springfield", Province: "mo", ISO2: "US"})
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].StateANSI != "MO" {
+			t.Errorf("Expected Springfield, MO, got %v", cities)
+		}
+	})
+
+	t.Run("Country only", func(t *testing.T) {
+		cities, err := FindCities(CityQuery{ISO2: "USA"})
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		for _, city := range cities {
+			if city.ISO2 != "US" {
+				t.Errorf("Expected only US cities, got %s", city.ISO2)
+			}
+		}
+		if len(cities) == 0 {
+			t.Error("Should find US cities")
+		}
+	})
+
+	t.Run("Fields combine with AND", func(t *testing.T) {
+		cities, err := FindCities(CityQuery{City: "Springfield", ISO2: "DE"})
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 0 {
+			t.Errorf("Expected no results, got %d", len(cities))
+		}
+	})
+
+	t.Run("Empty query", func(t *testing.T) {
+		cities, err := FindCities(CityQuery{})
+		if err != nil {
+			t.Errorf("Should not error: %v", err)
+		}
+		if len(cities) != 0 {
+			t.Errorf("Expected no results, got %d", len(cities))
+		}
+	})
+
+	t.Run("Invalid fields", func(t *testing.T) {
+		if _, err := FindCities(CityQuery{City: "<script>"}); err == nil {
+			t.Error("Should reject suspicious city")
+		}
+		if _, err := FindCities(CityQuery{City: "Paris", ISO2: "FRANCE"}); err == nil {
+			t.Error("Should reject invalid ISO code")
+		}
+	})
+}
+
+func TestListProvinces(t *testing.T) {
+	t.Run("US states", func(t *testing.T) {
+		provinces, err := ListProvinces("us")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if !sort.StringsAreSorted(provinces) {
+			t.Error("Provinces should be sorted")
+		}
+
+		seen := make(map[string]bool)
+		for _, province := range provinces {
+			if seen[province] {
+				t.Errorf("Duplicate province %s", province)
+			}
+			seen[province] = true
+		}
+		if !seen["Missouri"] {
+			t.Error("Should include Missouri")
+		}
+	})
+
+	t.Run("Invalid ISO code", func(t *testing.T) {
+		if _, err := ListProvinces("1"); err == nil {
+			t.Error("Should reject invalid ISO code")
+		}
+	})
+
+	t.Run("Empty ISO code", func(t *testing.T) {
+		provinces, err := ListProvinces("")
+		if err != nil {
+			t.Errorf("Should not error: %v", err)
+		}
+		if len(provinces) != 0 {
+			t.Errorf("Expected no provinces, got %d", len(provinces))
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package city
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// ScoredCity is a search result with its relevance score. Scores are only
+// comparable between results of the same query.
+type ScoredCity struct {
+	City  CityData
+	Score float64
+}
+
+// Relevance weights for partial matching
+const (
+	scoreExactWord   = 3.0  // Token equals a word of a field
+	scorePrefix      = 2.0  // Token is the prefix of a word of a field
+	scoreSubstring   = 1.0  // Token appears inside a field
+	cityFieldWeight  = 2.0  // Matches on the city name count double
+	scoreExactCity   = 10.0 // Whole query equals the city name
+	populationWeight = 0.5  // Multiplier of log10(population)
+)
+
+// scorePartialMatch scores a city matching all search terms. Each term adds
+// the score of its best match (exact word > prefix > substring, doubled on
+// the city name), a query equal to the city name earns a bonus, and larger
+// cities are boosted by the logarithm of their population.
+func scorePartialMatch(pipeline []NormalizationStage, city CityData, searchTerms []string) float64 {
+	cityName := normalize(pipeline, city.City)
+	otherFields := []string{
+		normalize(pipeline, city.StateANSI),
+		normalize(pipeline, city.Province),
+		normalize(pipeline, city.Country),
+	}
+
+	var score float64
+	for _, term := range searchTerms {
+		best := cityFieldWeight * termMatchScore(cityName, term)
+		for _, field := range otherFields {
+			best = math.Max(best, termMatchScore(field, term))
+		}
+		score += best
+	}
+
+	if cityName == strings.Join(searchTerms, " ") {
+		score += scoreExactCity
+	}
+
+	if city.Pop > 0 {
+		score += populationWeight * math.Log10(city.Pop)
+	}
+
+	return score
+}
+
+// termMatchScore returns how well a term matches a normalized field
+func termMatchScore(field, term string) float64 {
+	if !strings.Contains(field, term) {
+		return 0
+	}
+
+	best := scoreSubstring
+	for _, word := range strings.Fields(field) {
+		if word == term {
+			return scoreExactWord
+		}
+		if strings.HasPrefix(word, term) {
+			best = scorePrefix
+		}
+	}
+	return best
+}
+
+// sortByScore sorts results by descending score, keeping dataset order on ties
+func sortByScore(results []ScoredCity) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
@@ -0,0 +1,104 @@
+package city
+
+import (
+	"testing"
+)
+
+func TestTermMatchScore(t *testing.T) {
+	tests := []struct {
+		field, term string
+		expected    float64
+	}{
+		{"new york", "york", scoreExactWord},
+		{"yorkton", "york", scorePrefix},
+		{"east london", "lon", scorePrefix},
+		{"new london", "ondo", scoreSubstring},
+		{"chicago", "york", 0},
+	}
+
+	for _, tt := range tests {
+		if got := termMatchScore(tt.field, tt.term); got != tt.expected {
+			t.Errorf("termMatchScore(%q, %q) = %f, expected %f", tt.field, tt.term, got, tt.expected)
+		}
+	}
+}
+
+func TestScorePartialMatch(t *testing.T) {
+	pipeline := DefaultNormalization()
+
+	t.Run("Exact city beats substring", func(t *testing.T) {
+		exact := CityData{City: "London", Pop: 1000}
+		partial := CityData{City: "New London", Pop: 1000}
+		terms := []string{"london"}
+
+		if scorePartialMatch(pipeline, exact, terms) <= scorePartialMatch(pipeline, partial, terms) {
+			t.Error("Exact city name should score higher")
+		}
+	})
+
+	t.Run("Population breaks ties", func(t *testing.T) {
+		big := CityData{City: "Paris", Pop: 5000000}
+		small := CityData{City: "Paris", Pop: 25000}
+		terms := []string{"paris"}
+
+		if scorePartialMatch(pipeline, big, terms) <= scorePartialMatch(pipeline, small, terms) {
+			t.Error("Larger city should score higher")
+		}
+	})
+
+	t.Run("City field outweighs province", func(t *testing.T) {
+		cityMatch := CityData{City: "York", Province: "Pennsylvania"}
+		provinceMatch := CityData{City: "Buffalo", Province: "New York"}
+		terms := []string{"york"}
+
+		if scorePartialMatch(pipeline, cityMatch, terms) <= scorePartialMatch(pipeline, provinceMatch, terms) {
+			t.Error("City name match should score higher")
+		}
+	})
+}
+
+func TestFindFromCityStateProvinceScored(t *testing.T) {
+	t.Run("Results are sorted by score", func(t *testing.T) {
+		results, err := FindFromCityStateProvinceScored("london")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(results) < 2 {
+			t.Fatalf("Expected several results, got %d", len(results))
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i].Score > results[i-1].Score {
+				t.Fatalf("Results not sorted at %d: %f > %f", i, results[i].Score, results[i-1].Score)
+			}
+		}
+		if results[0].City.ISO2 != "GB" {
+			t.Errorf("Expected London, GB first, got %s, %s", results[0].City.City, results[0].City.ISO2)
+		}
+	})
+
+	t.Run("Partial matches rank below exact matches", func(t *testing.T) {
+		cities, err := FindFromCityStateProvince("york")
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) == 0 || cities[0].City != "York" {
+			t.Errorf("Expected York first, got %v", cities)
+		}
+	})
+
+	t.Run("Empty search string", func(t *testing.T) {
+		results, err := FindFromCityStateProvinceScored("")
+		if err != nil {
+			t.Errorf("Should not error: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Expected no results, got %d", len(results))
+		}
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		if _, err := FindFromCityStateProvinceScored("<script>"); err == nil {
+			t.Error("Should reject suspicious input")
+		}
+	})
+}
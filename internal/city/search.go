@@ -1,51 +1,109 @@
 package city
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
 
-// LookupViaCity searches for cities by exact city name match
-func LookupViaCity(cityName string) ([]CityData, error) {
+// LookupViaCity searches for cities by exact city name match, including
+// alternate and localized names. Names are compared after normalization.
+func (c *Client) LookupViaCity(cityName string) ([]CityData, error) {
+	return c.LookupViaCityContext(context.Background(), cityName)
+}
+
+// LookupViaCityContext is like LookupViaCity but passes ctx to the client's
+// hooks and provider
+func (c *Client) LookupViaCityContext(ctx context.Context, cityName string) ([]CityData, error) {
+	trace := c.startLookup(ctx, "LookupViaCity", cityName)
+	results, cacheHit, err := c.lookupViaCity(trace.ctx, cityName)
+	trace.end(len(results), cacheHit, err)
+	return results, err
+}
+
+// lookupViaCity implements LookupViaCity and reports whether the result was cached
+func (c *Client) lookupViaCity(ctx context.Context, cityName string) ([]CityData, bool, error) {
 	// Validate and sanitize input
 	validatedInput, err := ValidateSearchInput(cityName, 100) // Max 100 chars for city name
 	if err != nil {
-		return nil, fmt.Errorf("invalid input: %w", err)
+		return nil, false, fmt.Errorf("invalid input: %w", err)
 	}
 
 	if validatedInput == "" {
-		return []CityData{}, nil
+		if err := c.emptyInputError("input"); err != nil {
+			return nil, false, err
+		}
+		return []CityData{}, false, nil
 	}
 
+	pipeline, generation := c.snapshot()
+
 	// Check cache first
-	cacheKey := "city:" + strings.ToLower(validatedInput)
-	if cached, exists := GetCachedResult(cacheKey); exists {
-		return cached, nil
+	cacheKey := c.nameKey(pipeline, validatedInput)
+	if cached, exists := c.cache.Get(cacheKey); exists {
+		if err := c.notFoundError(validatedInput, "lookup by city", len(cached)); err != nil {
+			return nil, true, err
+		}
+		return cached, true, nil
 	}
 
-	cities, err := LoadCityData()
+	results, err := c.source().ByName(ctx, validatedInput)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	c.cacheResult(cacheKey, results, generation)
 
-	var results []CityData
-	searchTerm := strings.ToLower(validatedInput)
+	if err := c.notFoundError(validatedInput, "lookup by city", len(results)); err != nil {
+		return nil, false, err
+	}
 
-	for _, city := range cities {
-		if strings.ToLower(city.City) == searchTerm {
-			results = append(results, city)
-		}
+	return results, false, nil
+}
+
+// FindFromCityStateProvince searches for cities using partial matching
+// across city, state, province, and country fields. Results are sorted by
+// relevance, most relevant first.
+func (c *Client) FindFromCityStateProvince(searchString string) ([]CityData, error) {
+	return c.FindFromCityStateProvinceContext(context.Background(), searchString)
+}
+
+// FindFromCityStateProvinceContext is like FindFromCityStateProvince but passes
+// ctx to the client's hooks and provider
+func (c *Client) FindFromCityStateProvinceContext(ctx context.Context, searchString string) ([]CityData, error) {
+	trace := c.startLookup(ctx, "FindFromCityStateProvince", searchString)
+	scored, err := c.findFromCityStateProvinceScored(trace.ctx, searchString)
+	trace.end(len(scored), false, err)
+	if err != nil {
+		return nil, err
 	}
 
-	// Cache the result
-	SetCachedResult(cacheKey, results)
+	results := make([]CityData, len(scored))
+	for i, result := range scored {
+		results[i] = result.City
+	}
 
 	return results, nil
 }
 
-// FindFromCityStateProvince searches for cities using partial matching
-// across city, state, province, and country fields
-func FindFromCityStateProvince(searchString string) ([]CityData, error) {
+// FindFromCityStateProvinceScored works like FindFromCityStateProvince and
+// also returns the relevance score of each result, so callers can apply
+// a threshold
+func (c *Client) FindFromCityStateProvinceScored(searchString string) ([]ScoredCity, error) {
+	return c.FindFromCityStateProvinceScoredContext(context.Background(), searchString)
+}
+
+// FindFromCityStateProvinceScoredContext is like
+// FindFromCityStateProvinceScored but passes ctx to the client's hooks and
+// provider
+func (c *Client) FindFromCityStateProvinceScoredContext(ctx context.Context, searchString string) ([]ScoredCity, error) {
+	trace := c.startLookup(ctx, "FindFromCityStateProvinceScored", searchString)
+	results, err := c.findFromCityStateProvinceScored(trace.ctx, searchString)
+	trace.end(len(results), false, err)
+	return results, err
+}
+
+// findFromCityStateProvinceScored implements FindFromCityStateProvinceScored
+func (c *Client) findFromCityStateProvinceScored(ctx context.Context, searchString string) ([]ScoredCity, error) {
 	// Validate and sanitize input
 	validatedInput, err := ValidateSearchInput(searchString, 200) // Max 200 chars for search string
 	if err != nil {
@@ -53,28 +111,54 @@ func FindFromCityStateProvince(searchString string) ([]CityData, error) {
 	}
 
 	if validatedInput == "" {
-		return []CityData{}, nil
+		if err := c.emptyInputError("input"); err != nil {
+			return nil, err
+		}
+		return []ScoredCity{}, nil
 	}
 
-	cities, err := LoadCityData()
+	cities, err := c.allCities(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var results []CityData
-	searchTerms := strings.Fields(strings.ToLower(validatedInput))
+	pipeline := c.currentPipeline()
+	searchTerms := strings.Fields(normalize(pipeline, validatedInput))
 
+	var results []ScoredCity
 	for _, city := range cities {
-		if findPartialMatch(city, searchTerms) {
-			results = append(results, city)
+		if findPartialMatch(pipeline, city, searchTerms) {
+			results = append(results, ScoredCity{
+				City:  city,
+				Score: scorePartialMatch(pipeline, city, searchTerms),
+			})
 		}
 	}
 
+	if err := c.notFoundError(validatedInput, "partial match", len(results)); err != nil {
+		return nil, err
+	}
+
+	sortByScore(results)
 	return results, nil
 }
 
 // FindFromIsoCode searches for cities by ISO2 or ISO3 country codes
-func FindFromIsoCode(isoCode string) ([]CityData, error) {
+func (c *Client) FindFromIsoCode(isoCode string) ([]CityData, error) {
+	return c.FindFromIsoCodeContext(context.Background(), isoCode)
+}
+
+// FindFromIsoCodeContext is like FindFromIsoCode but passes ctx to the client's
+// hooks and provider
+func (c *Client) FindFromIsoCodeContext(ctx context.Context, isoCode string) ([]CityData, error) {
+	trace := c.startLookup(ctx, "FindFromIsoCode", isoCode)
+	results, err := c.findFromIsoCode(trace.ctx, isoCode)
+	trace.end(len(results), false, err)
+	return results, err
+}
+
+// findFromIsoCode implements FindFromIsoCode
+func (c *Client) findFromIsoCode(ctx context.Context, isoCode string) ([]CityData, error) {
 	// Validate ISO code
 	validatedCode, err := ValidateISOCode(isoCode)
 	if err != nil {
@@ -82,28 +166,67 @@ func FindFromIsoCode(isoCode string) ([]CityData, error) {
 	}
 
 	if validatedCode == "" {
+		if err := c.emptyInputError("isoCode"); err != nil {
+			return nil, err
+		}
 		return []CityData{}, nil
 	}
 
-	cities, err := LoadCityData()
+	results, err := c.source().ByISO(ctx, validatedCode)
 	if err != nil {
 		return nil, err
 	}
 
-	var results []CityData
-	searchCode := strings.ToLower(validatedCode)
+	if err := c.notFoundError(validatedCode, "lookup by ISO code", len(results)); err != nil {
+		return nil, err
+	}
 
-	for _, city := range cities {
-		if strings.ToLower(city.ISO2) == searchCode || strings.ToLower(city.ISO3) == searchCode {
-			results = append(results, city)
+	return results, nil
+}
+
+// FindFromTimezone returns the cities in an IANA timezone such as
+// "America/Chicago", compared case-insensitively, most populous first
+func (c *Client) FindFromTimezone(timezone string) ([]CityData, error) {
+	return c.FindFromTimezoneContext(context.Background(), timezone)
+}
+
+// FindFromTimezoneContext is like FindFromTimezone but passes ctx to the
+// client's hooks and provider
+func (c *Client) FindFromTimezoneContext(ctx context.Context, timezone string) ([]CityData, error) {
+	trace := c.startLookup(ctx, "FindFromTimezone", timezone)
+	results, err := c.findFromTimezone(trace.ctx, timezone)
+	trace.end(len(results), false, err)
+	return results, err
+}
+
+// findFromTimezone implements FindFromTimezone
+func (c *Client) findFromTimezone(ctx context.Context, timezone string) ([]CityData, error) {
+	validatedTimezone, err := ValidateSearchInput(timezone, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	if validatedTimezone == "" {
+		if err := c.emptyInputError("timezone"); err != nil {
+			return nil, err
 		}
+		return []CityData{}, nil
+	}
+
+	results, err := c.source().ByTimezone(ctx, validatedTimezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.notFoundError(validatedTimezone, "lookup by timezone", len(results)); err != nil {
+		return nil, err
 	}
 
 	return results, nil
 }
 
 // findPartialMatch checks if all search terms are found in the city's searchable fields
-func findPartialMatch(city CityData, searchTerms []string) bool {
+func findPartialMatch(pipeline []NormalizationStage, city CityData, searchTerms []string) bool {
 	// Create a combined searchable text from all relevant fields
 	searchableFields := []string{
 		city.City,
@@ -112,7 +235,7 @@ func findPartialMatch(city CityData, searchTerms []string) bool {
 		city.Country,
 	}
 
-	combinedText := strings.ToLower(strings.Join(searchableFields, " "))
+	combinedText := normalize(pipeline, strings.Join(searchableFields, " "))
 
 	// Check if all search terms are found in the combined text
 	for _, term := range searchTerms {
@@ -124,34 +247,72 @@ func findPartialMatch(city CityData, searchTerms []string) bool {
 	return true
 }
 
-// SearchCities provides a flexible search function with options
-func SearchCities(query string, options SearchOptions) ([]CityData, error) {
+// SearchCities provides a flexible search function with options. Unless the
+// search is case-sensitive, the query and fields are normalized first.
+func (c *Client) SearchCities(query string, options SearchOptions) ([]CityData, error) {
+	return c.SearchCitiesContext(context.Background(), query, options)
+}
+
+// SearchCitiesContext is like SearchCities but passes ctx to the client's hooks
+// and provider
+func (c *Client) SearchCitiesContext(ctx context.Context, query string, options SearchOptions) ([]CityData, error) {
+	trace := c.startLookup(ctx, "SearchCities", query)
+	results, err := c.searchCities(trace.ctx, query, options)
+	trace.end(len(results), false, err)
+	return results, err
+}
+
+// searchCities implements SearchCities
+func (c *Client) searchCities(ctx context.Context, query string, options SearchOptions) ([]CityData, error) {
 	if query == "" {
+		if err := c.emptyInputError("query"); err != nil {
+			return nil, err
+		}
 		return []CityData{}, nil
 	}
 
-	cities, err := LoadCityData()
+	cities, err := c.allCities(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var results []CityData
-	searchQuery := query
-	if !options.CaseSensitive {
-		searchQuery = strings.ToLower(searchQuery)
+	pipeline := c.currentPipeline()
+	if options.CaseSensitive {
+		pipeline = nil
+	}
+	searchQuery := normalize(pipeline, query)
+	if options.Glob {
+		searchQuery = normalizeGlob(pipeline, query)
 	}
 
+	var results []CityData
 	for _, city := range cities {
-		if matchesCity(city, searchQuery, options) {
+		if matchesCity(pipeline, city, searchQuery, options) && matchesSizeClasses(city, options.SizeClasses) && matchesMinDensity(city, options.MinDensity) {
 			results = append(results, city)
 		}
 	}
 
+	if options.Deduplicate {
+		results = deduplicate(c.currentPipeline(), results)
+	}
+
+	if err := c.notFoundError(query, "search", len(results)); err != nil {
+		return nil, err
+	}
+
+	if options.FlagTimezoneWarnings {
+		flagTimezoneWarnings(results)
+	}
+
+	if options.Language != "" {
+		results = LocalizeCities(results, options.Language)
+	}
+
 	return results, nil
 }
 
 // matchesCity checks if a city matches the search criteria
-func matchesCity(city CityData, query string, options SearchOptions) bool {
+func matchesCity(pipeline []NormalizationStage, city CityData, query string, options SearchOptions) bool {
 	searchableFields := []string{
 		city.City,
 		city.CityASCII,
@@ -161,14 +322,18 @@ func matchesCity(city CityData, query string, options SearchOptions) bool {
 		city.ISO2,
 		city.ISO3,
 	}
+	for _, alt := range city.AlternateNames {
+		searchableFields = append(searchableFields, alt.Name)
+	}
 
 	for _, field := range searchableFields {
-		fieldValue := field
-		if !options.CaseSensitive {
-			fieldValue = strings.ToLower(fieldValue)
-		}
+		fieldValue := normalize(pipeline, field)
 
-		if options.ExactMatch {
+		if options.Glob {
+			if globMatch(query, fieldValue) {
+				return true
+			}
+		} else if options.ExactMatch {
 			if fieldValue == query {
 				return true
 			}
@@ -0,0 +1,66 @@
+package city
+
+// SizeClass classifies cities by population
+type SizeClass string
+
+const (
+	SizeClassMegacity SizeClass = "megacity" // 10 million inhabitants or more
+	SizeClassLarge    SizeClass = "large"    // 1 million up to 10 million
+	SizeClassMedium   SizeClass = "medium"   // 100,000 up to 1 million
+	SizeClassSmall    SizeClass = "small"    // Fewer than 100,000
+)
+
+// Population thresholds for the size classes
+const (
+	MegacityPopulation   = 10_000_000
+	LargeCityPopulation  = 1_000_000
+	MediumCityPopulation = 100_000
+)
+
+// SizeClass returns the city's size class based on its population
+func (c CityData) SizeClass() SizeClass {
+	switch {
+	case c.Pop >= MegacityPopulation:
+		return SizeClassMegacity
+	case c.Pop >= LargeCityPopulation:
+		return SizeClassLarge
+	case c.Pop >= MediumCityPopulation:
+		return SizeClassMedium
+	default:
+		return SizeClassSmall
+	}
+}
+
+// Density returns the population per square kilometer, or 0 when the city's
+// area is unknown
+func (c CityData) Density() float64 {
+	if c.AreaKm2 <= 0 {
+		return 0
+	}
+	return c.Pop / c.AreaKm2
+}
+
+// matchesMinDensity checks if the city's density is at least minDensity; a
+// non-positive minimum matches every city, including those of unknown area
+func matchesMinDensity(city CityData, minDensity float64) bool {
+	if minDensity <= 0 {
+		return true
+	}
+	return city.AreaKm2 > 0 && city.Density() >= minDensity
+}
+
+// matchesSizeClasses checks if the city belongs to one of the size classes;
+// an empty list matches every city
+func matchesSizeClasses(city CityData, classes []SizeClass) bool {
+	if len(classes) == 0 {
+		return true
+	}
+
+	class := city.SizeClass()
+	for _, c := range classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package city
+
+import (
+	"testing"
+)
+
+func TestSizeClass(t *testing.T) {
+	tests := []struct {
+		pop      float64
+		expected SizeClass
+	}{
+		{22006299.5, SizeClassMegacity},
+		{MegacityPopulation, SizeClassMegacity},
+		{5915976, SizeClassLarge},
+		{LargeCityPopulation - 1, SizeClassMedium},
+		{MediumCityPopulation, SizeClassMedium},
+		{2997, SizeClassSmall},
+		{0, SizeClassSmall},
+	}
+
+	for _, tt := range tests {
+		if got := (CityData{Pop: tt.pop}).SizeClass(); got != tt.expected {
+			t.Errorf("SizeClass for population %.0f = %s, expected %s", tt.pop, got, tt.expected)
+		}
+	}
+}
+
+func TestDensity(t *testing.T) {
+	t.Run("Known area", func(t *testing.T) {
+		city := CityData{Pop: 1000, AreaKm2: 4}
+		if d := city.Density(); d != 250 {
+			t.Errorf("Expected 250, got %f", d)
+		}
+	})
+
+	t.Run("Unknown area", func(t *testing.T) {
+		city := CityData{Pop: 1000}
+		if d := city.Density(); d != 0 {
+			t.Errorf("Expected 0, got %f", d)
+		}
+	})
+
+	t.Run("Supplemental areas are loaded", func(t *testing.T) {
+		cities, err := FindCities(CityQuery{City: "Manila", ISO2: "PH"})
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].AreaKm2 == 0 {
+			t.Fatalf("Expected Manila with area, got %v", cities)
+		}
+		if cities[0].Density() < 10000 {
+			t.Errorf("Expected Manila to be dense, got %f", cities[0].Density())
+		}
+	})
+}
+
+func TestSearchCitiesSizeClasses(t *testing.T) {
+	t.Run("Filter by size class", func(t *testing.T) {
+		options := DefaultSearchOptions()
+		options.SizeClasses = []SizeClass{SizeClassMegacity}
+
+		cities, err := SearchCities("united states", options)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) == 0 {
+			t.Fatal("Should find US megacities")
+		}
+		for _, city := range cities {
+			if city.SizeClass() != SizeClassMegacity {
+				t.Errorf("Expected megacity, got %s (%s)", city.City, city.SizeClass())
+			}
+		}
+	})
+
+	t.Run("Minimum density", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{
+			{City: "Dense Springfield", Pop: 2_000_000, AreaKm2: 200, ISO3: "USA"},
+			{City: "Sprawling Springfield", Pop: 2_000_000, AreaKm2: 4000, ISO3: "USA"},
+			{City: "Unmeasured Springfield", Pop: 2_000_000, ISO3: "USA"},
+		}})
+
+		options := DefaultSearchOptions()
+		options.SizeClasses = []SizeClass{SizeClassLarge}
+		cities, err := client.SearchCities("springfield", options)
+		if err != nil || len(cities) != 3 {
+			t.Fatalf("Expected the size class to match all 3 cities, got %v, %v", cities, err)
+		}
+
+		options.MinDensity = 5000
+		cities, err = client.SearchCities("springfield", options)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].City != "Dense Springfield" {
+			t.Errorf("Expected only Dense Springfield, got %v", cities)
+		}
+	})
+
+	t.Run("Minimum density with supplemental areas", func(t *testing.T) {
+		options := DefaultSearchOptions()
+		options.MinDensity = 20000
+		cities, err := SearchCities("philippines", options)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		found := false
+		for _, city := range cities {
+			if city.Density() < 20000 {
+				t.Errorf("Expected at least 20000/km², got %s (%f)", city.City, city.Density())
+			}
+			found = found || city.City == "Manila"
+		}
+		if !found {
+			t.Errorf("Expected Manila, got %v", cities)
+		}
+	})
+
+	t.Run("Empty size classes match all", func(t *testing.T) {
+		if !matchesSizeClasses(CityData{Pop: 1}, nil) {
+			t.Error("Empty size classes should match")
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package city
+
+import (
+	"context"
+	"strings"
+)
+
+// CitiesInSameTimezone returns up to limit other cities in the timezone of
+// the named city, most populous first, e.g. for "this meeting time also
+// applies in ..." features. Ambiguous names resolve to the most populous
+// match. The cities come from the timezone index, not a scan.
+func (c *Client) CitiesInSameTimezone(cityName string, limit int) ([]CityData, error) {
+	return c.CitiesInSameTimezoneContext(context.Background(), cityName, limit)
+}
+
+// CitiesInSameTimezoneContext is like CitiesInSameTimezone but passes ctx to
+// the client's hooks and provider
+func (c *Client) CitiesInSameTimezoneContext(ctx context.Context, cityName string, limit int) ([]CityData, error) {
+	trace := c.startLookup(ctx, "CitiesInSameTimezone", cityName)
+	results, err := c.citiesInSameTimezone(trace.ctx, cityName, limit)
+	trace.end(len(results), false, err)
+	return results, err
+}
+
+// citiesInSameTimezone implements CitiesInSameTimezone
+func (c *Client) citiesInSameTimezone(ctx context.Context, cityName string, limit int) ([]CityData, error) {
+	if limit < 1 {
+		return nil, NewValidationError("limit", "limit must be at least 1", limit)
+	}
+
+	matches, _, err := c.lookupViaCity(ctx, cityName)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, NewSearchError(cityName, "cities in same timezone", ErrNotFound)
+	}
+
+	city := mostPopulous(matches)
+	if city.Timezone == "" {
+		return []CityData{}, nil
+	}
+
+	return c.otherCitiesInTimezone(ctx, city, limit)
+}
+
+// otherCitiesInTimezone returns up to limit cities in the timezone of city,
+// most populous first, skipping records of city itself. A dataset may hold
+// several such records, so the bucket is walked until limit cities are
+// collected, copying only those from the dataset's index.
+func (c *Client) otherCitiesInTimezone(ctx context.Context, city CityData, limit int) ([]CityData, error) {
+	results := []CityData{}
+	if c.provider != nil {
+		cities, err := c.provider.ByTimezone(ctx, city.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range cities {
+			if len(results) == limit {
+				break
+			}
+			if !sameCity(other, city) {
+				results = append(results, other)
+			}
+		}
+		return results, nil
+	}
+
+	err := c.view(func(d *dataset, _ []NormalizationStage) {
+		for _, i := range d.byTimezone[strings.ToLower(city.Timezone)] {
+			if len(results) == limit {
+				break
+			}
+			if !sameCity(d.cities[i], city) {
+				results = append(results, d.cities[i])
+			}
+		}
+	})
+	return results, err
+}
+
+// sameCity reports whether two records describe the same city
+func sameCity(a, b CityData) bool {
+	return a.City == b.City && a.ISO3 == b.ISO3 && a.Province == b.Province && a.Lat == b.Lat && a.Lng == b.Lng
+}
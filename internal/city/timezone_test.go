@@ -0,0 +1,88 @@
+package city
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCitiesInSameTimezone(t *testing.T) {
+	t.Run("bundled dataset", func(t *testing.T) {
+		cities, err := CitiesInSameTimezone("Chicago", 5)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 5 {
+			t.Fatalf("Expected 5 cities, got %d", len(cities))
+		}
+		for i, city := range cities {
+			if city.Timezone != "America/Chicago" {
+				t.Errorf("Expected America/Chicago, got %s for %s", city.Timezone, city.City)
+			}
+			if city.City == "Chicago" && city.Province == "Illinois" {
+				t.Error("Expected the queried city to be excluded")
+			}
+			if i > 0 && cities[i-1].Pop < city.Pop {
+				t.Errorf("Expected descending population, got %s before %s", cities[i-1].City, city.City)
+			}
+		}
+	})
+
+	t.Run("custom dataset", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: providerCities})
+		cities, err := client.CitiesInSameTimezone("chicago", 10)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 1 || cities[0].City != "Milwaukee" {
+			t.Errorf("Expected [Milwaukee], got %v", cities)
+		}
+
+		if cities, _ := client.CitiesInSameTimezone("Suva", 10); len(cities) != 0 {
+			t.Errorf("Expected no other cities in Pacific/Fiji, got %v", cities)
+		}
+	})
+
+	t.Run("added cities are indexed", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: providerCities})
+		client.AddCity(CityData{City: "Madison", ISO2: "US", ISO3: "USA", Province: "Wisconsin", Timezone: "America/Chicago"})
+
+		cities, err := client.CitiesInSameTimezone("Milwaukee", 10)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(cities) != 2 {
+			t.Errorf("Expected Chicago and Madison, got %v", cities)
+		}
+	})
+
+	t.Run("duplicate records of the city are skipped", func(t *testing.T) {
+		chicago := providerCities[0]
+		cities := []CityData{chicago, chicago, chicago}
+		cities = append(cities, providerCities[1:]...)
+
+		for name, client := range map[string]*Client{
+			"dataset":  NewClient(ClientOptions{Cities: cities}),
+			"provider": NewClient(ClientOptions{Provider: NewDatasetProvider(cities)}),
+		} {
+			results, err := client.CitiesInSameTimezone("Chicago", 1)
+			if err != nil {
+				t.Fatalf("%s: should not error: %v", name, err)
+			}
+			if len(results) != 1 || results[0].City != "Milwaukee" {
+				t.Errorf("%s: expected [Milwaukee], got %v", name, results)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := CitiesInSameTimezone("Chicago", 0); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput for limit 0, got %v", err)
+		}
+		if _, err := CitiesInSameTimezone("Atlantis", 5); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+		if _, err := CitiesInSameTimezone("", 5); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for an empty name, got %v", err)
+		}
+	})
+}
@@ -1,26 +1,71 @@
 package city
 
-// CityData represents a city with its timezone and geographical information
+// CityData represents a city with its timezone and geographical information.
+// Its JSON form uses the keys and key order of the city-timezones npm package,
+// see MarshalJSON.
 type CityData struct {
+	City          string  `json:"city"`
+	CityASCII     string  `json:"city_ascii"`
 	Lat           float64 `json:"lat"`
 	Lng           float64 `json:"lng"`
 	Pop           float64 `json:"pop"` // Changed to float64 to handle decimal values
-	City          string  `json:"city"`
+	Country       string  `json:"country"`
 	ISO2          string  `json:"iso2"`
 	ISO3          string  `json:"iso3"`
-	Country       string  `json:"country"`
-	Timezone      string  `json:"timezone"`
 	Province      string  `json:"province"`
-	ExactCity     string  `json:"exactCity"`
-	CityASCII     string  `json:"city_ascii"`
-	StateANSI     string  `json:"state_ansi"`
-	ExactProvince string  `json:"exactProvince"`
+	ExactCity     string  `json:"exactCity,omitempty"`
+	ExactProvince string  `json:"exactProvince,omitempty"`
+	StateANSI     string  `json:"state_ansi,omitempty"`
+	Timezone      string  `json:"timezone"`
+
+	// AlternateNames holds other spellings and localized names of the city
+	AlternateNames []AlternateName `json:"alt_names,omitempty"`
+
+	// AreaKm2 is the city's land area in square kilometers, 0 when unknown
+	AreaKm2 float64 `json:"area_km2,omitempty"`
+
+	// TimezoneWarning is set on search results when SearchOptions.FlagTimezoneWarnings
+	// is enabled and the timezone is implausible for the city's coordinates
+	TimezoneWarning bool `json:"timezone_warning,omitempty"`
+}
+
+// AlternateName is an alternative or localized name for a city
+type AlternateName struct {
+	Name string `json:"name"`
+	Lang string `json:"lang,omitempty"` // BCP 47 language tag, empty for spelling variants
 }
 
 // SearchOptions provides configuration for search operations
 type SearchOptions struct {
 	CaseSensitive bool
 	ExactMatch    bool
+
+	// Glob treats '*' (any characters) and '?' (one character) in the query as
+	// wildcards, e.g. "san *o" or "new?rk". Patterns must match a whole field,
+	// so use "san *" for a prefix. ExactMatch is ignored.
+	Glob bool
+
+	// Language localizes result names to the given BCP 47 language tag
+	// (e.g. "de", "pt-BR") when an alternate name in that language exists
+	Language string
+
+	// FlagTimezoneWarnings sets CityData.TimezoneWarning on results whose
+	// timezone offset deviates from solar time by more than DefaultMaxTimezoneDeviation
+	FlagTimezoneWarnings bool
+
+	// SizeClasses restricts results to cities of the given size classes;
+	// empty means no restriction
+	SizeClasses []SizeClass
+
+	// MinDensity restricts results to cities with at least this many
+	// inhabitants per km², e.g. to tell dense cores from sprawling
+	// municipalities of the same size class. Cities of unknown area are
+	// excluded when it is set; 0 means no restriction.
+	MinDensity float64
+
+	// Deduplicate keeps only the most populous city per name and country,
+	// e.g. one Springfield in the US
+	Deduplicate bool
 }
 
 // DefaultSearchOptions returns the default search configuration
@@ -0,0 +1,196 @@
+package city
+
+import (
+	"sort"
+	"time"
+
+	"github.com/richoandika/city-timezones-go/data"
+)
+
+// EmbeddedTZData reports whether the binary was built with the citytz_tzdata
+// tag, which embeds Go's copy of the IANA timezone database (time/tzdata, about
+// 450KB) for systems without one, such as scratch containers. Without it,
+// timezones are loaded from the system database.
+const EmbeddedTZData = data.TZData
+
+// timezoneAliases maps legacy IANA timezone names, kept as links in the tz
+// database's "backward" file, to their current names
+var timezoneAliases = map[string]string{
+	"Africa/Asmera":         "Africa/Asmara",
+	"Africa/Timbuktu":       "Africa/Bamako",
+	"America/Buenos_Aires":  "America/Argentina/Buenos_Aires",
+	"America/Catamarca":     "America/Argentina/Catamarca",
+	"America/Coral_Harbour": "America/Atikokan",
+	"America/Cordoba":       "America/Argentina/Cordoba",
+	"America/Ensenada":      "America/Tijuana",
+	"America/Fort_Wayne":    "America/Indiana/Indianapolis",
+	"America/Godthab":       "America/Nuuk",
+	"America/Indianapolis":  "America/Indiana/Indianapolis",
+	"America/Jujuy":         "America/Argentina/Jujuy",
+	"America/Louisville":    "America/Kentucky/Louisville",
+	"America/Mendoza":       "America/Argentina/Mendoza",
+	"America/Montreal":      "America/Toronto",
+	"America/Nipigon":       "America/Toronto",
+	"America/Pangnirtung":   "America/Iqaluit",
+	"America/Porto_Acre":    "America/Rio_Branco",
+	"America/Rainy_River":   "America/Winnipeg",
+	"America/Santa_Isabel":  "America/Tijuana",
+	"America/Thunder_Bay":   "America/Toronto",
+	"America/Yellowknife":   "America/Edmonton",
+	"Asia/Ashkhabad":        "Asia/Ashgabat",
+	"Asia/Calcutta":         "Asia/Kolkata",
+	"Asia/Choibalsan":       "Asia/Ulaanbaatar",
+	"Asia/Chongqing":        "Asia/Shanghai",
+	"Asia/Chungking":        "Asia/Shanghai",
+	"Asia/Dacca":            "Asia/Dhaka",
+	"Asia/Harbin":           "Asia/Shanghai",
+	"Asia/Istanbul":         "Europe/Istanbul",
+	"Asia/Kashgar":          "Asia/Urumqi",
+	"Asia/Katmandu":         "Asia/Kathmandu",
+	"Asia/Macao":            "Asia/Macau",
+	"Asia/Rangoon":          "Asia/Yangon",
+	"Asia/Saigon":           "Asia/Ho_Chi_Minh",
+	"Asia/Tel_Aviv":         "Asia/Jerusalem",
+	"Asia/Thimbu":           "Asia/Thimphu",
+	"Asia/Ujung_Pandang":    "Asia/Makassar",
+	"Asia/Ulan_Bator":       "Asia/Ulaanbaatar",
+	"Atlantic/Faeroe":       "Atlantic/Faroe",
+	"Australia/ACT":         "Australia/Sydney",
+	"Australia/Canberra":    "Australia/Sydney",
+	"Europe/Belfast":        "Europe/London",
+	"Europe/Kiev":           "Europe/Kyiv",
+	"Europe/Uzhgorod":       "Europe/Kyiv",
+	"Europe/Zaporozhye":     "Europe/Kyiv",
+	"Pacific/Enderbury":     "Pacific/Kanton",
+	"Pacific/Ponape":        "Pacific/Pohnpei",
+	"Pacific/Samoa":         "Pacific/Pago_Pago",
+	"Pacific/Truk":          "Pacific/Chuuk",
+	"US/Alaska":             "America/Anchorage",
+	"US/Arizona":            "America/Phoenix",
+	"US/Central":            "America/Chicago",
+	"US/Eastern":            "America/New_York",
+	"US/Hawaii":             "Pacific/Honolulu",
+	"US/Mountain":           "America/Denver",
+	"US/Pacific":            "America/Los_Angeles",
+}
+
+// legacyTimezones maps current timezone names to their legacy names, for tz
+// databases that predate a rename
+var legacyTimezones = func() map[string][]string {
+	legacy := make(map[string][]string)
+	for old, current := range timezoneAliases {
+		legacy[current] = append(legacy[current], old)
+	}
+	for _, names := range legacy {
+		sort.Strings(names)
+	}
+	return legacy
+}()
+
+// TimezoneAliases returns a copy of the table mapping legacy timezone names to
+// their current names
+func TimezoneAliases() map[string]string {
+	aliases := make(map[string]string, len(timezoneAliases))
+	for old, current := range timezoneAliases {
+		aliases[old] = current
+	}
+	return aliases
+}
+
+// CanonicalTimezone returns the current name of a legacy timezone name, or the
+// name itself
+func CanonicalTimezone(name string) string {
+	if current, ok := timezoneAliases[name]; ok {
+		return current
+	}
+	return name
+}
+
+// LoadLocation loads a timezone like time.LoadLocation, falling back to the
+// current name of a legacy zone, and to the legacy names of a zone the
+// installed tz database does not know yet. The error of the original name is
+// returned if no name loads.
+func LoadLocation(name string) (*time.Location, error) {
+	location, _, err := loadLocation(name)
+	return location, err
+}
+
+// loadLocation implements LoadLocation and returns the name that was loaded
+func loadLocation(name string) (*time.Location, string, error) {
+	location, err := time.LoadLocation(name)
+	if err == nil {
+		return location, name, nil
+	}
+
+	for _, alias := range timezoneAlternatives(name) {
+		if location, aliasErr := time.LoadLocation(alias); aliasErr == nil {
+			return location, alias, nil
+		}
+	}
+	return nil, "", err
+}
+
+// timezoneAlternatives returns the other names of a timezone, current first
+func timezoneAlternatives(name string) []string {
+	var names []string
+	if current, ok := timezoneAliases[name]; ok {
+		names = append(names, current)
+	}
+	return append(names, legacyTimezones[name]...)
+}
+
+// TimezoneIssue describes a dataset timezone name that is outdated or cannot be
+// loaded by name from the current tz database
+type TimezoneIssue struct {
+	Timezone  string // Name as stored in the dataset
+	Cities    int    // Number of cities using the name
+	Canonical string // Current name if Timezone is a legacy name, else empty
+	Resolved  string // Name LoadLocation fell back to, if Timezone did not load
+	Err       error  // Set when no name of the zone could be loaded
+}
+
+// ValidateTimezones checks every timezone name in the client's dataset against
+// the tz database available to the binary (see EmbeddedTZData). It reports the
+// legacy names and the names that do not load, sorted by name.
+func (c *Client) ValidateTimezones() ([]TimezoneIssue, error) {
+	cities, err := c.Cities()
+	if err != nil {
+		return nil, err
+	}
+	return validateTimezones(cities), nil
+}
+
+// validateTimezones returns the timezone issues of the cities
+func validateTimezones(cities []CityData) []TimezoneIssue {
+	counts := make(map[string]int)
+	for _, city := range cities {
+		if city.Timezone != "" {
+			counts[city.Timezone]++
+		}
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []TimezoneIssue
+	for _, name := range names {
+		issue := TimezoneIssue{Timezone: name, Cities: counts[name]}
+		if current, ok := timezoneAliases[name]; ok {
+			issue.Canonical = current
+		}
+
+		if _, loaded, err := loadLocation(name); err != nil {
+			issue.Err = err
+		} else if loaded != name {
+			issue.Resolved = loaded
+		}
+
+		if issue.Canonical != "" || issue.Resolved != "" || issue.Err != nil {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
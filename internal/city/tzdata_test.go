@@ -0,0 +1,100 @@
+package city
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimezoneAliases(t *testing.T) {
+	for old, current := range TimezoneAliases() {
+		if _, ok := timezoneAliases[current]; ok {
+			t.Errorf("Alias %s points to the legacy name %s", old, current)
+		}
+		if _, err := time.LoadLocation(current); err != nil {
+			t.Errorf("Current name %s of %s does not load: %v", current, old, err)
+		}
+	}
+
+	aliases := TimezoneAliases()
+	aliases["Asia/Calcutta"] = "Mars/Olympus_Mons"
+	if CanonicalTimezone("Asia/Calcutta") != "Asia/Kolkata" {
+		t.Error("Expected TimezoneAliases to return a copy")
+	}
+}
+
+func TestCanonicalTimezone(t *testing.T) {
+	tests := map[string]string{
+		"Asia/Calcutta": "Asia/Kolkata",
+		"Europe/Kiev":   "Europe/Kyiv",
+		"Europe/Berlin": "Europe/Berlin",
+		"":              "",
+	}
+	for name, expected := range tests {
+		if got := CanonicalTimezone(name); got != expected {
+			t.Errorf("CanonicalTimezone(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestLoadLocation(t *testing.T) {
+	location, err := LoadLocation("Asia/Calcutta")
+	if err != nil {
+		t.Fatalf("Should not error: %v", err)
+	}
+	if _, offset := time.Date(2024, time.January, 1, 0, 0, 0, 0, location).Zone(); offset != 5*3600+1800 {
+		t.Errorf("Expected +05:30, got %d seconds", offset)
+	}
+
+	if _, err := LoadLocation("Mars/Olympus_Mons"); err == nil {
+		t.Error("Expected an error for an unknown zone")
+	}
+
+	if got := timezoneAlternatives("Europe/Kyiv"); len(got) != 3 || got[0] != "Europe/Kiev" {
+		t.Errorf("Expected the legacy names of Europe/Kyiv, got %v", got)
+	}
+	if got := timezoneAlternatives("Asia/Calcutta"); len(got) != 1 || got[0] != "Asia/Kolkata" {
+		t.Errorf("Expected the current name of Asia/Calcutta, got %v", got)
+	}
+}
+
+func TestValidateTimezones(t *testing.T) {
+	t.Run("custom dataset", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{
+			{City: "Berlin", Timezone: "Europe/Berlin"},
+			{City: "Kolkata", Timezone: "Asia/Calcutta"},
+			{City: "Howrah", Timezone: "Asia/Calcutta"},
+			{City: "Olympus", Timezone: "Mars/Olympus_Mons"},
+			{City: "Nowhere"},
+		}})
+
+		issues, err := client.ValidateTimezones()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if len(issues) != 2 {
+			t.Fatalf("Expected 2 issues, got %+v", issues)
+		}
+
+		legacy := issues[0]
+		if legacy.Timezone != "Asia/Calcutta" || legacy.Cities != 2 || legacy.Canonical != "Asia/Kolkata" || legacy.Err != nil {
+			t.Errorf("Unexpected legacy zone issue: %+v", legacy)
+		}
+
+		unknown := issues[1]
+		if unknown.Timezone != "Mars/Olympus_Mons" || unknown.Canonical != "" || unknown.Err == nil {
+			t.Errorf("Unexpected unknown zone issue: %+v", unknown)
+		}
+	})
+
+	t.Run("bundled dataset", func(t *testing.T) {
+		issues, err := ValidateTimezones()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		for _, issue := range issues {
+			if issue.Err != nil {
+				t.Errorf("Zone %s does not load: %v", issue.Timezone, issue.Err)
+			}
+		}
+	})
+}
@@ -0,0 +1,416 @@
+package city
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxUpdateSize is the default limit for a downloaded dataset (64MB)
+	DefaultMaxUpdateSize = 64 << 20
+
+	// DefaultMaxDecompressedSize is the default limit for a gzip-compressed
+	// dataset after decompression (256MB)
+	DefaultMaxDecompressedSize = 256 << 20
+
+	// DefaultWatchInterval is the default time between update checks of Watch
+	DefaultWatchInterval = time.Hour
+)
+
+// ErrVerificationFailed is returned when a downloaded dataset does not match
+// its checksum or signature
+var ErrVerificationFailed = errors.New("dataset verification failed")
+
+// UpdateOptions configures how a dataset is downloaded and verified. At least
+// one of SHA256, ChecksumURL or PublicKey must be set unless
+// InsecureSkipVerify is.
+type UpdateOptions struct {
+	// HTTPClient performs the downloads, nil uses http.DefaultClient. Use the
+	// context passed to the update for timeouts.
+	HTTPClient *http.Client
+
+	// SHA256 is the expected hex-encoded SHA-256 checksum of the dataset
+	SHA256 string
+
+	// ChecksumURL points to a checksum file in sha256sum format, whose first
+	// field is the hex-encoded SHA-256 checksum of the dataset
+	ChecksumURL string
+
+	// PublicKey verifies an Ed25519 signature of the dataset, downloaded from
+	// SignatureURL (default: the dataset URL with ".sig" appended). The
+	// signature may be raw or base64-encoded.
+	PublicKey    ed25519.PublicKey
+	SignatureURL string
+
+	// InsecureSkipVerify accepts datasets without checksum or signature
+	InsecureSkipVerify bool
+
+	// MaxSize limits the size of the downloaded dataset in bytes, 0 uses
+	// DefaultMaxUpdateSize
+	MaxSize int64
+
+	// MaxDecompressedSize limits the size of a gzip-compressed dataset after
+	// decompression in bytes, 0 uses DefaultMaxDecompressedSize
+	MaxDecompressedSize int64
+
+	// MinCities rejects datasets with fewer cities, e.g. truncated files.
+	// Values below 1 require at least one city.
+	MinCities int
+}
+
+// DefaultUpdateOptions returns the default update configuration. A checksum
+// or public key must still be set.
+func DefaultUpdateOptions() UpdateOptions {
+	return UpdateOptions{
+		MaxSize:             DefaultMaxUpdateSize,
+		MaxDecompressedSize: DefaultMaxDecompressedSize,
+		MinCities:           1,
+	}
+}
+
+// DatasetUpdate describes the result of an update check
+type DatasetUpdate struct {
+	// Updated reports whether the client's dataset was replaced. It is false
+	// when the server reports the dataset unchanged or its checksum equals
+	// the one applied last.
+	Updated bool
+
+	SHA256 string // Checksum of the downloaded dataset, empty if not downloaded
+	Cities int    // Number of cities in the downloaded dataset
+}
+
+// WatchOptions configures Watch
+type WatchOptions struct {
+	UpdateOptions
+
+	// Interval is the time between update checks, 0 uses DefaultWatchInterval
+	Interval time.Duration
+
+	// OnUpdate is called after each check that replaced the dataset
+	OnUpdate func(DatasetUpdate)
+
+	// OnError is called for each failed check. The current dataset stays in use.
+	OnError func(error)
+}
+
+// updateState remembers the last applied update, so unchanged datasets are
+// neither downloaded again nor swapped in. The ETag is only valid for the URL
+// it was received from.
+type updateState struct {
+	url    string
+	etag   string
+	sha256 string
+}
+
+// UpdateFromURL downloads a dataset in the cityMap.json format (optionally
+// gzip-compressed) from url, verifies and validates it, and atomically
+// replaces the client's dataset with it. Indexes are rebuilt, supplemental
+// alternate names and areas are applied, and the cache is cleared. On any
+// error the current dataset stays in use. It returns ErrProviderManaged for
+// clients with a provider.
+func (c *Client) UpdateFromURL(ctx context.Context, url string, options UpdateOptions) (DatasetUpdate, error) {
+	if c.provider != nil {
+		return DatasetUpdate{}, ErrProviderManaged
+	}
+	if options.SHA256 == "" && options.ChecksumURL == "" && options.PublicKey == nil && !options.InsecureSkipVerify {
+		return DatasetUpdate{}, NewValidationError("options", "a checksum or public key is required to verify the dataset", nil)
+	}
+
+	// The download runs without holding the client's lock, so a slow server
+	// does not block dataset changes. Only the swap compares against the
+	// update applied last.
+	c.mu.RLock()
+	previous := c.update
+	c.mu.RUnlock()
+
+	var ifNoneMatch string
+	if previous.url == url {
+		ifNoneMatch = previous.etag
+	}
+
+	body, etag, err := download(ctx, options.HTTPClient, url, ifNoneMatch, maxUpdateSize(options))
+	if err != nil {
+		return DatasetUpdate{}, NewDataLoadError("update", err)
+	}
+	if body == nil {
+		return DatasetUpdate{}, nil // Not modified
+	}
+
+	sum := sha256.Sum256(body)
+	result := DatasetUpdate{SHA256: hex.EncodeToString(sum[:])}
+
+	if err := verifyDataset(ctx, url, body, result.SHA256, options); err != nil {
+		return result, NewDataLoadError("update", err)
+	}
+
+	cities, err := decodeDownloadedDataset(body, maxDecompressedSize(options))
+	if err != nil {
+		return result, NewDataLoadError("update", err)
+	}
+	result.Cities = len(cities)
+
+	if err := validateDataset(cities, options.MinCities); err != nil {
+		return result, err
+	}
+
+	if err := loadSupplementalData(cities); err != nil {
+		return result, NewDataLoadError("update", err)
+	}
+	result.Updated = c.applyUpdate(cities, updateState{url: url, etag: etag, sha256: result.SHA256})
+
+	return result, nil
+}
+
+// applyUpdate replaces the client's dataset with a downloaded one, unless
+// the update applied last had the same checksum, and reports whether it did.
+// Concurrent updates are applied in the order they finish.
+func (c *Client) applyUpdate(cities []CityData, state updateState) bool {
+	c.mu.Lock()
+	if state.sha256 == c.update.sha256 {
+		c.update = state
+		c.mu.Unlock()
+		return false
+	}
+
+	c.data = newDataset(cities, c.pipeline, false)
+	c.origin = datasetOrigin{sha256: state.sha256, url: state.url}
+	c.update = state
+	c.generation++
+	c.mu.Unlock()
+
+	c.cache.Clear()
+	return true
+}
+
+// Watch checks url for a new dataset immediately and then at every interval,
+// applying updates with UpdateFromURL, until ctx is cancelled. It blocks and
+// returns the context's error, or ErrProviderManaged at once for clients with
+// a provider.
+func (c *Client) Watch(ctx context.Context, url string, options WatchOptions) error {
+	if c.provider != nil {
+		return ErrProviderManaged
+	}
+
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		update, err := c.UpdateFromURL(ctx, url, options.UpdateOptions)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			if options.OnError != nil {
+				options.OnError(err)
+			}
+		case update.Updated:
+			if options.OnUpdate != nil {
+				options.OnUpdate(update)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// maxUpdateSize returns the configured size limit of a downloaded dataset
+func maxUpdateSize(options UpdateOptions) int64 {
+	if options.MaxSize <= 0 {
+		return DefaultMaxUpdateSize
+	}
+	return options.MaxSize
+}
+
+// maxDecompressedSize returns the configured size limit of a decompressed dataset
+func maxDecompressedSize(options UpdateOptions) int64 {
+	if options.MaxDecompressedSize <= 0 {
+		return DefaultMaxDecompressedSize
+	}
+	return options.MaxDecompressedSize
+}
+
+// download fetches url, sending etag for a conditional request. It returns
+// a nil body if the server reports the resource unchanged.
+func download(ctx context.Context, client *http.Client, url, etag string, maxSize int64) ([]byte, string, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, etag, nil
+	default:
+		return nil, "", fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+
+	// Read one byte more than allowed to detect oversized responses
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, "", fmt.Errorf("%s exceeds the maximum size of %d bytes", url, maxSize)
+	}
+
+	return body, resp.Header.Get("ETag"), nil
+}
+
+// verifyDataset checks the dataset against every configured checksum and signature
+func verifyDataset(ctx context.Context, url string, body []byte, sum string, options UpdateOptions) error {
+	if options.SHA256 != "" && !strings.EqualFold(strings.TrimSpace(options.SHA256), sum) {
+		return fmt.Errorf("%w: checksum %s does not match %s", ErrVerificationFailed, sum, options.SHA256)
+	}
+
+	if options.ChecksumURL != "" {
+		checksum, _, err := download(ctx, options.HTTPClient, options.ChecksumURL, "", 4096)
+		if err != nil {
+			return fmt.Errorf("failed to download checksum: %w", err)
+		}
+		fields := strings.Fields(string(checksum))
+		if len(fields) == 0 || !strings.EqualFold(fields[0], sum) {
+			return fmt.Errorf("%w: checksum %s does not match %s", ErrVerificationFailed, sum, options.ChecksumURL)
+		}
+	}
+
+	if options.PublicKey != nil {
+		if len(options.PublicKey) != ed25519.PublicKeySize {
+			return NewValidationError("PublicKey", "invalid Ed25519 public key size", len(options.PublicKey))
+		}
+
+		signatureURL := options.SignatureURL
+		if signatureURL == "" {
+			signatureURL = url + ".sig"
+		}
+		signature, _, err := download(ctx, options.HTTPClient, signatureURL, "", 4096)
+		if err != nil {
+			return fmt.Errorf("failed to download signature: %w", err)
+		}
+		if !ed25519.Verify(options.PublicKey, body, decodeSignature(signature)) {
+			return fmt.Errorf("%w: invalid signature from %s", ErrVerificationFailed, signatureURL)
+		}
+	}
+
+	return nil
+}
+
+// decodeSignature returns a raw signature, decoding base64 if needed
+func decodeSignature(signature []byte) []byte {
+	if len(signature) == ed25519.SignatureSize {
+		return signature
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return signature
+	}
+	return decoded
+}
+
+// decodeDownloadedDataset decodes a dataset, decompressing it if it is
+// gzipped. Decompression fails after maxSize bytes, so small archives cannot
+// expand into huge datasets.
+func decodeDownloadedDataset(body []byte, maxSize int64) ([]CityData, error) {
+	var r io.Reader = bytes.NewReader(body)
+	if bytes.HasPrefix(body, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress dataset: %w", err)
+		}
+		defer zr.Close()
+		r = &sizeLimitedReader{r: zr, remaining: maxSize + 1, maxSize: maxSize}
+	}
+
+	return decodeCityData(r, newStringInterner())
+}
+
+// sizeLimitedReader fails once more than maxSize bytes were read, unlike
+// io.LimitReader, which would end the stream early and look like truncation
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64 // One more than the bytes left to read
+	maxSize   int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining <= 0 {
+		return n, fmt.Errorf("decompressed dataset exceeds the maximum size of %d bytes", l.maxSize)
+	}
+	return n, err
+}
+
+// validateDataset rejects datasets with too few cities or invalid records,
+// including timezones that cannot be loaded
+func validateDataset(cities []CityData, minCities int) error {
+	if minCities < 1 {
+		minCities = 1
+	}
+	if len(cities) < minCities {
+		return NewValidationError("dataset", fmt.Sprintf("contains %d cities, expected at least %d", len(cities), minCities), nil)
+	}
+
+	timezones := make(map[string]error)
+	for i, city := range cities {
+		switch {
+		case strings.TrimSpace(city.City) == "":
+			return NewValidationError("city", fmt.Sprintf("record %d has no city name", i), nil)
+		case math.IsNaN(city.Lat) || city.Lat < -90 || city.Lat > 90:
+			return NewValidationError("lat", fmt.Sprintf("record %d (%s) has an invalid latitude", i, city.City), city.Lat)
+		case math.IsNaN(city.Lng) || city.Lng < -180 || city.Lng > 180:
+			return NewValidationError("lng", fmt.Sprintf("record %d (%s) has an invalid longitude", i, city.City), city.Lng)
+		}
+
+		if city.Timezone == "" {
+			continue
+		}
+		err, checked := timezones[city.Timezone]
+		if !checked {
+			_, err = LoadLocation(city.Timezone)
+			timezones[city.Timezone] = err
+		}
+		if err != nil {
+			return NewValidationError("timezone", fmt.Sprintf("record %d (%s) has an unknown timezone", i, city.City), city.Timezone)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,398 @@
+package city
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const updateDataset = `[
+	{"city": "Atlantis", "lat": 31.5, "lng": -24.5, "pop": 20000, "iso2": "AT", "iso3": "ATL", "country": "Atlantis", "timezone": "Atlantic/Azores"},
+	{"city": "Munich", "lat": 48.13, "lng": 11.57, "pop": 1260391, "iso2": "DE", "iso3": "DEU", "country": "Germany", "timezone": "Europe/Berlin", "province": "Bayern"}
+]`
+
+// datasetServer serves a dataset with its checksum and signature and counts
+// the full downloads of the dataset
+type datasetServer struct {
+	mu        sync.Mutex
+	dataset   []byte
+	signature []byte
+	downloads int
+}
+
+func (s *datasetServer) set(dataset []byte, key ed25519.PrivateKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dataset = dataset
+	if key != nil {
+		s.signature = ed25519.Sign(key, dataset)
+	}
+}
+
+func (s *datasetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sum := sha256.Sum256(s.dataset)
+	checksum := hex.EncodeToString(sum[:])
+
+	switch r.URL.Path {
+	case "/cities.json", "/cities.json.gz":
+		etag := `"` + checksum + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		s.downloads++
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write(s.dataset)
+	case "/cities.json.sha256":
+		_, _ = w.Write([]byte(checksum + "  cities.json\n"))
+	case "/cities.json.sig":
+		_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(s.signature)))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUpdateFromURL(t *testing.T) {
+	ctx := context.Background()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &datasetServer{}
+	handler.set([]byte(updateDataset), private)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	url := server.URL + "/cities.json"
+
+	t.Run("Verification is required", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		_, err := client.UpdateFromURL(ctx, url, DefaultUpdateOptions())
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("Checksum", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		options := DefaultUpdateOptions()
+		options.SHA256 = sha256Hex([]byte(updateDataset))
+
+		update, err := client.UpdateFromURL(ctx, url, options)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if !update.Updated || update.Cities != 2 {
+			t.Errorf("Expected an update with 2 cities, got %+v", update)
+		}
+
+		cities, _ := client.LookupViaCity("Atlantis")
+		if len(cities) != 1 {
+			t.Errorf("Expected Atlantis after the update, got %d", len(cities))
+		}
+
+		// Supplemental alternate names are applied to the new dataset
+		cities, _ = client.LookupViaCity("München")
+		if len(cities) != 1 {
+			t.Errorf("Expected München to find Munich, got %d", len(cities))
+		}
+
+		info, err := client.DatasetInfo()
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if info.SHA256 != options.SHA256 || info.UpdateURL != url || info.Cities != 2 || info.Version != "" {
+			t.Errorf("Expected the applied dataset to be described, got %+v", info)
+		}
+	})
+
+	t.Run("Checksum mismatch keeps the dataset", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{{City: "Chicago"}}})
+		options := DefaultUpdateOptions()
+		options.SHA256 = sha256Hex([]byte("other"))
+
+		_, err := client.UpdateFromURL(ctx, url, options)
+		if !errors.Is(err, ErrVerificationFailed) {
+			t.Errorf("Expected ErrVerificationFailed, got %v", err)
+		}
+		if n, _ := client.Len(); n != 1 {
+			t.Errorf("Expected the dataset to stay in use, got %d cities", n)
+		}
+	})
+
+	t.Run("Checksum file", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		options := DefaultUpdateOptions()
+		options.ChecksumURL = url + ".sha256"
+
+		if update, err := client.UpdateFromURL(ctx, url, options); err != nil || !update.Updated {
+			t.Errorf("Expected an update, got %+v, %v", update, err)
+		}
+	})
+
+	t.Run("Signature", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		options := DefaultUpdateOptions()
+		options.PublicKey = public
+
+		if update, err := client.UpdateFromURL(ctx, url, options); err != nil || !update.Updated {
+			t.Errorf("Expected an update, got %+v, %v", update, err)
+		}
+
+		otherPublic, _, _ := ed25519.GenerateKey(nil)
+		options.PublicKey = otherPublic
+		other := NewClient(ClientOptions{Cities: []CityData{}})
+		if _, err := other.UpdateFromURL(ctx, url, options); !errors.Is(err, ErrVerificationFailed) {
+			t.Errorf("Expected ErrVerificationFailed, got %v", err)
+		}
+	})
+
+	t.Run("Unchanged dataset is not downloaded again", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		options := DefaultUpdateOptions()
+		options.SHA256 = sha256Hex([]byte(updateDataset))
+
+		handler.mu.Lock()
+		before := handler.downloads
+		handler.mu.Unlock()
+
+		if _, err := client.UpdateFromURL(ctx, url, options); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		update, err := client.UpdateFromURL(ctx, url, options)
+		if err != nil || update.Updated {
+			t.Errorf("Expected no update, got %+v, %v", update, err)
+		}
+
+		handler.mu.Lock()
+		defer handler.mu.Unlock()
+		if handler.downloads-before != 1 {
+			t.Errorf("Expected 1 download, got %d", handler.downloads-before)
+		}
+	})
+
+	t.Run("ETags are only sent to their URL", func(t *testing.T) {
+		var mu sync.Mutex
+		var ifNoneMatch []string
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+			mu.Unlock()
+			if r.Header.Get("If-None-Match") != "" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"1"`)
+			_, _ = w.Write([]byte(`[{"city": "Lemuria", "lat": 1, "lng": 80, "timezone": "Indian/Maldives"}]`))
+		}))
+		defer other.Close()
+
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		options := DefaultUpdateOptions()
+		options.InsecureSkipVerify = true
+
+		if _, err := client.UpdateFromURL(ctx, url, options); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		update, err := client.UpdateFromURL(ctx, other.URL, options)
+		if err != nil || !update.Updated {
+			t.Fatalf("Expected an update from the second URL, got %+v, %v", update, err)
+		}
+		if _, err := client.UpdateFromURL(ctx, other.URL, options); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(ifNoneMatch) != 2 || ifNoneMatch[0] != "" || ifNoneMatch[1] != `"1"` {
+			t.Errorf("Expected no ETag and then the second URL's ETag, got %q", ifNoneMatch)
+		}
+	})
+
+	t.Run("Local changes are replaced by the next update", func(t *testing.T) {
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		options := DefaultUpdateOptions()
+		options.SHA256 = sha256Hex([]byte(updateDataset))
+
+		if _, err := client.UpdateFromURL(ctx, url, options); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		client.SetCities(nil)
+
+		update, err := client.UpdateFromURL(ctx, url, options)
+		if err != nil || !update.Updated {
+			t.Errorf("Expected an update, got %+v, %v", update, err)
+		}
+		if n, _ := client.Len(); n != 2 {
+			t.Errorf("Expected 2 cities, got %d", n)
+		}
+	})
+
+	t.Run("A hung download does not block dataset changes", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			_, _ = w.Write([]byte(updateDataset))
+		}))
+		defer server.Close()
+		defer close(release)
+
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		options := DefaultUpdateOptions()
+		options.SHA256 = sha256Hex([]byte(updateDataset))
+		go func() { _, _ = client.UpdateFromURL(ctx, server.URL, options) }()
+		<-started
+
+		done := make(chan error)
+		go func() { done <- client.AddCity(CityData{City: "Springfield"}) }()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Should not error: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("AddCity blocked on the pending update")
+		}
+	})
+
+	t.Run("Invalid datasets are rejected", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			dataset string
+			options UpdateOptions
+		}{
+			{"Too few cities", updateDataset, UpdateOptions{MinCities: 3}},
+			{"Empty", `[]`, UpdateOptions{}},
+			{"Missing name", `[{"city": "", "lat": 1, "lng": 1}]`, UpdateOptions{}},
+			{"Invalid latitude", `[{"city": "Nowhere", "lat": 91, "lng": 1}]`, UpdateOptions{}},
+			{"Unknown timezone", `[{"city": "Nowhere", "lat": 1, "lng": 1, "timezone": "Mars/Olympus_Mons"}]`, UpdateOptions{}},
+			{"Too large", updateDataset, UpdateOptions{MaxSize: 10}},
+			{"Not JSON", `<html></html>`, UpdateOptions{}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(tt.dataset))
+				}))
+				defer server.Close()
+
+				client := NewClient(ClientOptions{Cities: []CityData{{City: "Chicago"}}})
+				tt.options.InsecureSkipVerify = true
+				if _, err := client.UpdateFromURL(ctx, server.URL, tt.options); err == nil {
+					t.Error("Expected an error")
+				}
+				if n, _ := client.Len(); n != 1 {
+					t.Errorf("Expected the dataset to stay in use, got %d cities", n)
+				}
+			})
+		}
+	})
+
+	t.Run("Gzip-compressed dataset", func(t *testing.T) {
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		_, _ = zw.Write([]byte(updateDataset))
+		_ = zw.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(compressed.Bytes())
+		}))
+		defer server.Close()
+
+		client := NewClient(ClientOptions{Cities: []CityData{}})
+		options := DefaultUpdateOptions()
+		options.SHA256 = sha256Hex(compressed.Bytes())
+
+		if update, err := client.UpdateFromURL(ctx, server.URL, options); err != nil || update.Cities != 2 {
+			t.Errorf("Expected 2 cities, got %+v, %v", update, err)
+		}
+
+		options.MaxDecompressedSize = int64(len(updateDataset))
+		client = NewClient(ClientOptions{Cities: []CityData{}})
+		if _, err := client.UpdateFromURL(ctx, server.URL, options); err != nil {
+			t.Errorf("Expected a dataset of exactly MaxDecompressedSize to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("Decompressed size is limited", func(t *testing.T) {
+		// A few KB of gzip expanding to 10MB of whitespace
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		_, _ = zw.Write(bytes.Repeat([]byte(" "), 10<<20))
+		_, _ = zw.Write([]byte(updateDataset))
+		_ = zw.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(compressed.Bytes())
+		}))
+		defer server.Close()
+
+		client := NewClient(ClientOptions{Cities: []CityData{{City: "Chicago"}}})
+		options := DefaultUpdateOptions()
+		options.SHA256 = sha256Hex(compressed.Bytes())
+		options.MaxDecompressedSize = 1 << 20
+
+		_, err := client.UpdateFromURL(ctx, server.URL, options)
+		if err == nil || !strings.Contains(err.Error(), "maximum size") {
+			t.Errorf("Expected the decompressed size limit to be exceeded, got %v", err)
+		}
+		if n, _ := client.Len(); n != 1 {
+			t.Errorf("Expected the dataset to stay in use, got %d cities", n)
+		}
+	})
+}
+
+func TestWatch(t *testing.T) {
+	handler := &datasetServer{}
+	handler.set([]byte(updateDataset), nil)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(ClientOptions{Cities: []CityData{}})
+	updates := make(chan DatasetUpdate, 10)
+
+	options := WatchOptions{Interval: 10 * time.Millisecond, OnUpdate: func(u DatasetUpdate) { updates <- u }}
+	options.InsecureSkipVerify = true
+
+	done := make(chan error)
+	go func() { done <- client.Watch(ctx, server.URL+"/cities.json", options) }()
+
+	if update := <-updates; update.Cities != 2 {
+		t.Errorf("Expected 2 cities, got %+v", update)
+	}
+
+	// A changed dataset is picked up by a later check
+	handler.set([]byte(`[{"city": "Atlantis", "lat": 31.5, "lng": -24.5}]`), nil)
+	if update := <-updates; update.Cities != 1 {
+		t.Errorf("Expected 1 city, got %+v", update)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
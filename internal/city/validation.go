@@ -2,6 +2,7 @@ package city
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"unicode/utf8"
 )
@@ -100,6 +101,54 @@ func ValidateISOCode(isoCode string) (string, error) {
 	}
 }
 
+// ValidateAirportCode validates IATA (3 letters) and ICAO (4 letters)
+// airport codes and returns them uppercase
+func ValidateAirportCode(code string) (string, error) {
+	normalized := strings.TrimSpace(strings.ToUpper(code))
+	if normalized == "" {
+		return "", nil
+	}
+
+	if len(normalized) != 3 && len(normalized) != 4 {
+		return "", ValidationError{
+			Field:   "airportCode",
+			Message: "airport code must be 3 (IATA) or 4 (ICAO) characters",
+		}
+	}
+
+	for _, r := range normalized {
+		if r < 'A' || r > 'Z' {
+			return "", ValidationError{
+				Field:   "airportCode",
+				Message: "invalid airport code format",
+			}
+		}
+	}
+
+	return normalized, nil
+}
+
+// ValidateCoordinates validates a latitude and longitude in decimal degrees
+func ValidateCoordinates(lat, lng float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return ValidationError{
+			Field:   "lat",
+			Message: "latitude must be between -90 and 90",
+			Value:   lat,
+		}
+	}
+
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return ValidationError{
+			Field:   "lng",
+			Message: "longitude must be between -180 and 180",
+			Value:   lng,
+		}
+	}
+
+	return nil
+}
+
 // isValidISO2Code checks if the string is a valid ISO2 country code format
 func isValidISO2Code(code string) bool {
 	if len(code) != 2 {
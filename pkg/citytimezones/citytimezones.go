@@ -1,37 +1,367 @@
 package citytimezones
 
 import (
+	"cmp"
+	"context"
+	"time"
+
 	"github.com/richoandika/city-timezones-go/internal/city"
 )
 
 // CityData represents a city with its timezone and geographical information
 type CityData = city.CityData
 
+// AlternateName is an alternative or localized name for a city
+type AlternateName = city.AlternateName
+
+// SizeClass classifies cities by population
+type SizeClass = city.SizeClass
+
+// Size classes for SearchOptions.SizeClasses
+const (
+	SizeClassMegacity = city.SizeClassMegacity // 10 million inhabitants or more
+	SizeClassLarge    = city.SizeClassLarge    // 1 million up to 10 million
+	SizeClassMedium   = city.SizeClassMedium   // 100,000 up to 1 million
+	SizeClassSmall    = city.SizeClassSmall    // Fewer than 100,000
+)
+
 // SearchOptions provides configuration for search operations
 type SearchOptions = city.SearchOptions
 
-// LookupViaCity searches for cities by exact city name match
+// Client runs city searches with its own normalization pipeline and result
+// cache. The package-level functions use DefaultClient().
+type Client = city.Client
+
+// ClientOptions provides configuration for a Client
+type ClientOptions = city.ClientOptions
+
+// NormalizationStage is a named step of a Client's normalization pipeline
+type NormalizationStage = city.NormalizationStage
+
+// Names of the built-in normalization stages
+const (
+	StageLowercase        = city.StageLowercase
+	StageFoldDiacritics   = city.StageFoldDiacritics
+	StageStripPunctuation = city.StageStripPunctuation
+	StageExpandSynonyms   = city.StageExpandSynonyms
+	StageTransliterate    = city.StageTransliterate
+)
+
+// CommonSynonyms are abbreviations frequently used in city names, for use
+// with ExpandSynonymsStage
+var CommonSynonyms = city.CommonSynonyms
+
+// CyrillicTransliteration is a simple Russian/Ukrainian transliteration
+// table for use with TransliterateStage
+var CyrillicTransliteration = city.CyrillicTransliteration
+
+// Sentinel errors for use with errors.Is
+var (
+	// ErrInvalidInput matches every input validation error
+	ErrInvalidInput = city.ErrInvalidInput
+
+	// ErrInvalidISOCode matches validation errors for malformed ISO country codes
+	ErrInvalidISOCode = city.ErrInvalidISOCode
+
+	// ErrNotFound is returned when nothing matches a query that requires a
+	// result, or any query on a client created with ErrorOnNotFound
+	ErrNotFound = city.ErrNotFound
+
+	// ErrDatasetEmpty is returned by queries on a client created with
+	// ErrorOnEmptyDataset while its dataset has no cities
+	ErrDatasetEmpty = city.ErrDatasetEmpty
+
+	// ErrProviderManaged is returned by the methods changing a client's
+	// dataset when a DataProvider serves its cities
+	ErrProviderManaged = city.ErrProviderManaged
+
+	// ErrVerificationFailed is returned when a downloaded dataset does not
+	// match its checksum or signature
+	ErrVerificationFailed = city.ErrVerificationFailed
+)
+
+// ValidationError describes invalid input, e.g. for use with errors.As
+type ValidationError = city.ValidationError
+
+// SearchError describes a failed search, e.g. for use with errors.As
+type SearchError = city.SearchError
+
+// NewClient creates a new client with the given options. Set
+// ClientOptions.Cities to an empty slice to start with an empty dataset.
+func NewClient(options ClientOptions) *Client {
+	return city.NewClient(options)
+}
+
+// DefaultClientOptions returns the default client configuration
+func DefaultClientOptions() ClientOptions {
+	return city.DefaultClientOptions()
+}
+
+// DefaultClient returns the client used by the package-level functions
+func DefaultClient() *Client {
+	return city.DefaultClient()
+}
+
+// ClientConfig is a JSON-serializable snapshot of a client's configuration
+type ClientConfig = city.ClientConfig
+
+// StageConfig describes a normalization stage of a ClientConfig
+type StageConfig = city.StageConfig
+
+// ConfigSnapshot returns the default client's configuration: normalization
+// stages, cache size, error options and dataset identity
+func ConfigSnapshot() (ClientConfig, error) {
+	return city.DefaultClient().ConfigSnapshot()
+}
+
+// NewClientFromConfig creates a client from a configuration snapshot of the
+// embedded dataset. Use config.Options() for other datasets and providers.
+func NewClientFromConfig(config ClientConfig) (*Client, error) {
+	return city.NewClientFromConfig(config)
+}
+
+// Preload decodes and indexes the bundled dataset now rather than on the
+// first query. Without it the dataset is loaded lazily.
+func Preload() error {
+	return city.Preload()
+}
+
+// Hooks instruments a Client's lookups, e.g. for tracing or metrics
+type Hooks = city.Hooks
+
+// LookupStart describes a lookup that is about to run
+type LookupStart = city.LookupStart
+
+// LookupEnd describes a finished lookup
+type LookupEnd = city.LookupEnd
+
+// SetHooks sets the hooks instrumenting the default client's lookups, nil
+// removes them
+func SetHooks(hooks Hooks) {
+	city.SetHooks(hooks)
+}
+
+// DataProvider serves the cities a Client searches, set with
+// ClientOptions.Provider, e.g. to serve cities from a database
+type DataProvider = city.DataProvider
+
+// NameNormalizer is implemented by providers that normalize names with a
+// pipeline of their own, which clients adopt and cache ByName results under
+type NameNormalizer = city.NameNormalizer
+
+// NewDatasetProvider returns a provider serving cities from memory with the
+// default normalization. Nil cities use the bundled dataset.
+func NewDatasetProvider(cities []CityData) DataProvider {
+	return city.NewDatasetProvider(cities)
+}
+
+// DefaultNormalization returns the default pipeline, which only lowercases
+func DefaultNormalization() []NormalizationStage {
+	return city.DefaultNormalization()
+}
+
+// LowercaseStage lowercases the input
+func LowercaseStage() NormalizationStage {
+	return city.LowercaseStage()
+}
+
+// FoldDiacriticsStage replaces accented Latin letters with their ASCII base letters
+func FoldDiacriticsStage() NormalizationStage {
+	return city.FoldDiacriticsStage()
+}
+
+// StripPunctuationStage removes apostrophes, replaces other punctuation with
+// spaces and collapses whitespace
+func StripPunctuationStage() NormalizationStage {
+	return city.StripPunctuationStage()
+}
+
+// ExpandSynonymsStage replaces whole words found in synonyms with their expansion
+func ExpandSynonymsStage(synonyms map[string]string) NormalizationStage {
+	return city.ExpandSynonymsStage(synonyms)
+}
+
+// TransliterateStage replaces each rune found in table with its transliteration
+func TransliterateStage(table map[rune]string) NormalizationStage {
+	return city.TransliterateStage(table)
+}
+
+// SetNormalization replaces the default client's normalization pipeline
+func SetNormalization(stages ...NormalizationStage) {
+	city.SetNormalization(stages...)
+}
+
+// Normalization returns a copy of the default client's normalization pipeline
+func Normalization() []NormalizationStage {
+	return city.Normalization()
+}
+
+// LookupViaCity searches for cities by exact city name match, including
+// alternate and localized names such as "München" for Munich
 func LookupViaCity(cityName string) ([]CityData, error) {
 	return city.LookupViaCity(cityName)
 }
 
+// LookupViaCityContext is like LookupViaCity but passes ctx to the default
+// client's hooks and provider
+func LookupViaCityContext(ctx context.Context, cityName string) ([]CityData, error) {
+	return city.LookupViaCityContext(ctx, cityName)
+}
+
 // FindFromCityStateProvince searches for cities using partial matching
-// across city, state, province, and country fields
+// across city, state, province, and country fields, sorted by relevance
 func FindFromCityStateProvince(searchString string) ([]CityData, error) {
 	return city.FindFromCityStateProvince(searchString)
 }
 
+// FindFromCityStateProvinceContext is like FindFromCityStateProvince but passes
+// ctx to the default client's hooks and provider
+func FindFromCityStateProvinceContext(ctx context.Context, searchString string) ([]CityData, error) {
+	return city.FindFromCityStateProvinceContext(ctx, searchString)
+}
+
+// ScoredCity is a search result with its relevance score
+type ScoredCity = city.ScoredCity
+
+// FindFromCityStateProvinceScored works like FindFromCityStateProvince and
+// also returns the relevance score of each result, so callers can apply a threshold
+func FindFromCityStateProvinceScored(searchString string) ([]ScoredCity, error) {
+	return city.FindFromCityStateProvinceScored(searchString)
+}
+
+// FindFromCityStateProvinceScoredContext is like
+// FindFromCityStateProvinceScored but passes ctx to the default client's hooks
+// and provider
+func FindFromCityStateProvinceScoredContext(ctx context.Context, searchString string) ([]ScoredCity, error) {
+	return city.FindFromCityStateProvinceScoredContext(ctx, searchString)
+}
+
 // FindFromIsoCode searches for cities by ISO2 or ISO3 country codes
 func FindFromIsoCode(isoCode string) ([]CityData, error) {
 	return city.FindFromIsoCode(isoCode)
 }
 
+// FindFromIsoCodeContext is like FindFromIsoCode but passes ctx to the default
+// client's hooks and provider
+func FindFromIsoCodeContext(ctx context.Context, isoCode string) ([]CityData, error) {
+	return city.FindFromIsoCodeContext(ctx, isoCode)
+}
+
+// LookupViaAirportCode returns the city served by the airport with the given
+// IATA ("ORD") or ICAO ("KORD") code, including its timezone
+func LookupViaAirportCode(code string) ([]CityData, error) {
+	return city.LookupViaAirportCode(code)
+}
+
+// LookupViaAirportCodeContext is like LookupViaAirportCode but passes ctx to
+// the default client's hooks and provider
+func LookupViaAirportCodeContext(ctx context.Context, code string) ([]CityData, error) {
+	return city.LookupViaAirportCodeContext(ctx, code)
+}
+
+// FindFromTimezone returns the cities in an IANA timezone such as
+// "America/Chicago", compared case-insensitively, most populous first
+func FindFromTimezone(timezone string) ([]CityData, error) {
+	return city.FindFromTimezone(timezone)
+}
+
+// FindFromTimezoneContext is like FindFromTimezone but passes ctx to the
+// default client's hooks and provider
+func FindFromTimezoneContext(ctx context.Context, timezone string) ([]CityData, error) {
+	return city.FindFromTimezoneContext(ctx, timezone)
+}
+
+// CitiesInSameTimezone returns up to limit other cities in the IANA timezone
+// of the named city, most populous first. Ambiguous names resolve to the most
+// populous match.
+func CitiesInSameTimezone(cityName string, limit int) ([]CityData, error) {
+	return city.CitiesInSameTimezone(cityName, limit)
+}
+
+// CitiesInSameTimezoneContext is like CitiesInSameTimezone but passes ctx to
+// the default client's hooks and provider
+func CitiesInSameTimezoneContext(ctx context.Context, cityName string, limit int) ([]CityData, error) {
+	return city.CitiesInSameTimezoneContext(ctx, cityName, limit)
+}
+
+// NearestCities returns up to limit cities ordered by their great-circle
+// distance to the coordinates, nearest first
+func NearestCities(lat, lng float64, limit int) ([]CityData, error) {
+	return city.NearestCities(lat, lng, limit)
+}
+
+// NearestCitiesContext is like NearestCities but passes ctx to the default
+// client's hooks and provider
+func NearestCitiesContext(ctx context.Context, lat, lng float64, limit int) ([]CityData, error) {
+	return city.NearestCitiesContext(ctx, lat, lng, limit)
+}
+
+// ValidateCoordinates returns a ValidationError unless the latitude and
+// longitude are valid decimal degrees
+func ValidateCoordinates(lat, lng float64) error {
+	return city.ValidateCoordinates(lat, lng)
+}
+
+// Candidate is a city matching an ambiguous name, with a display label
+type Candidate = city.Candidate
+
+// Disambiguate returns the cities named cityName, most populous first, with
+// unique display labels such as "Springfield, MO, US" for pickers
+func Disambiguate(cityName string) ([]Candidate, error) {
+	return city.Disambiguate(cityName)
+}
+
+// DisambiguateContext is like Disambiguate but passes ctx to the default
+// client's hooks and provider
+func DisambiguateContext(ctx context.Context, cityName string) ([]Candidate, error) {
+	return city.DisambiguateContext(ctx, cityName)
+}
+
+// CityQuery describes a structured city search with AND semantics
+type CityQuery = city.CityQuery
+
+// FindCities searches for cities matching every non-empty field of the query,
+// e.g. CityQuery{City: "Springfield", Province: "Missouri"}
+func FindCities(query CityQuery) ([]CityData, error) {
+	return city.FindCities(query)
+}
+
+// FindCitiesContext is like FindCities but passes ctx to the default client's
+// hooks and provider
+func FindCitiesContext(ctx context.Context, query CityQuery) ([]CityData, error) {
+	return city.FindCitiesContext(ctx, query)
+}
+
+// ListProvinces returns the sorted, distinct province names of a country
+// identified by its ISO2 or ISO3 code
+func ListProvinces(isoCode string) ([]string, error) {
+	return city.ListProvinces(isoCode)
+}
+
+// ListProvincesContext is like ListProvinces but passes ctx to the default
+// client's hooks and provider
+func ListProvincesContext(ctx context.Context, isoCode string) ([]string, error) {
+	return city.ListProvincesContext(ctx, isoCode)
+}
+
 // SearchCities provides a flexible search function with options
 func SearchCities(query string, options SearchOptions) ([]CityData, error) {
 	return city.SearchCities(query, options)
 }
 
-// GetCityMapping returns all available cities
+// SearchCitiesContext is like SearchCities but passes ctx to the default
+// client's hooks and provider
+func SearchCitiesContext(ctx context.Context, query string, options SearchOptions) ([]CityData, error) {
+	return city.SearchCitiesContext(ctx, query, options)
+}
+
+// LocalizeCities returns a copy of cities with each name localized to the
+// given BCP 47 language tag where an alternate name is available
+func LocalizeCities(cities []CityData, lang string) []CityData {
+	return city.LocalizeCities(cities, lang)
+}
+
+// GetCityMapping returns all cities of the default client's dataset
 func GetCityMapping() ([]CityData, error) {
 	return city.GetCityData()
 }
@@ -41,9 +371,172 @@ func DefaultSearchOptions() SearchOptions {
 	return city.DefaultSearchOptions()
 }
 
+// DistanceBetween returns the great-circle (haversine) distance between two
+// cities in kilometers
+func DistanceBetween(cityA, cityB CityData) float64 {
+	return city.DistanceBetween(cityA, cityB)
+}
+
+// DistanceBetweenNames returns the distance in kilometers between two named
+// cities, resolving ambiguous names to the most populous match
+func DistanceBetweenNames(a, b string) (float64, error) {
+	return city.DistanceBetweenNames(a, b)
+}
+
+// Group is a key with its cities, see SortedGroups. It is a defined type
+// rather than an alias, since generic type aliases need Go 1.24.
+type Group[K cmp.Ordered] city.Group[K]
+
+// GroupBy groups cities by the key returned for each city, keeping the order
+// of cities within each group
+func GroupBy[K comparable](cities []CityData, key func(CityData) K) map[K][]CityData {
+	return city.GroupBy(cities, key)
+}
+
+// GroupByCountry groups cities by their ISO3 country code
+func GroupByCountry(cities []CityData) map[string][]CityData {
+	return city.GroupByCountry(cities)
+}
+
+// GroupByTimezone groups cities by their IANA timezone, cities without one
+// under ""
+func GroupByTimezone(cities []CityData) map[string][]CityData {
+	return city.GroupByTimezone(cities)
+}
+
+// SortedGroups returns the groups ordered by key, for stable output
+func SortedGroups[K cmp.Ordered](groups map[K][]CityData) []Group[K] {
+	sorted := city.SortedGroups(groups)
+	result := make([]Group[K], len(sorted))
+	for i, group := range sorted {
+		result[i] = Group[K](group)
+	}
+	return result
+}
+
+// DSTDetails describes the daylight saving time rules of a city's timezone for a year
+type DSTDetails = city.DSTDetails
+
+// DSTTransition is a change of UTC offset in a timezone
+type DSTTransition = city.DSTTransition
+
+// DSTInfo returns whether the named city observes DST in the given year, its
+// standard and daylight offsets, and the year's transition times. Ambiguous
+// names resolve to the most populous match.
+func DSTInfo(cityName string, year int) (DSTDetails, error) {
+	return city.DSTInfo(cityName, year)
+}
+
+// DSTInfoForCity returns the DST details of the city's timezone for the year
+func DSTInfoForCity(c CityData, year int) (DSTDetails, error) {
+	return city.DSTInfoForCity(c, year)
+}
+
+// TimezoneInconsistency describes a city whose stored timezone is implausible
+// for its coordinates
+type TimezoneInconsistency = city.TimezoneInconsistency
+
+// DefaultMaxTimezoneDeviation is the default tolerance between a city's
+// standard timezone offset and the solar time at its longitude
+const DefaultMaxTimezoneDeviation = city.DefaultMaxTimezoneDeviation
+
+// CheckTimezoneConsistency checks the dataset for cities whose timezone offset
+// deviates from solar time by more than maxDeviation (0 uses the default)
+func CheckTimezoneConsistency(maxDeviation time.Duration) ([]TimezoneInconsistency, error) {
+	return city.CheckTimezoneConsistency(maxDeviation)
+}
+
+// FindTimezoneInconsistencies checks the given cities for timezones that are
+// implausible for their coordinates (0 uses the default tolerance)
+func FindTimezoneInconsistencies(cities []CityData, maxDeviation time.Duration) []TimezoneInconsistency {
+	return city.FindTimezoneInconsistencies(cities, maxDeviation)
+}
+
+// EmbeddedTZData reports whether the binary was built with the citytz_tzdata
+// tag, which embeds the IANA timezone database for systems without one
+const EmbeddedTZData = city.EmbeddedTZData
+
+// TimezoneIssue describes a dataset timezone name that is outdated or cannot
+// be loaded by name
+type TimezoneIssue = city.TimezoneIssue
+
+// ValidateTimezones reports the dataset's legacy timezone names and the names
+// the current tz database cannot load, sorted by name
+func ValidateTimezones() ([]TimezoneIssue, error) {
+	return city.ValidateTimezones()
+}
+
+// TimezoneAliases returns a copy of the table mapping legacy timezone names to
+// their current names
+func TimezoneAliases() map[string]string {
+	return city.TimezoneAliases()
+}
+
+// CanonicalTimezone returns the current name of a legacy timezone name, or the
+// name itself
+func CanonicalTimezone(name string) string {
+	return city.CanonicalTimezone(name)
+}
+
+// LoadLocation loads a timezone like time.LoadLocation, falling back to the
+// current name of a legacy zone and to the legacy names of a zone the
+// installed tz database does not know yet
+func LoadLocation(name string) (*time.Location, error) {
+	return city.LoadLocation(name)
+}
+
+// DatasetMetadata describes the dataset embedded in the binary, or the
+// dataset a client answers from
+type DatasetMetadata = city.DatasetMetadata
+
+// DatasetInfo returns the version, commit, record count, source attribution
+// and generation date of the embedded dataset, or the checksum, URL and
+// record count of a dataset applied by UpdateDatasetFromURL
+func DatasetInfo() (DatasetMetadata, error) {
+	return city.DatasetInfo()
+}
+
+// UpdateOptions configures how a dataset is downloaded and verified
+type UpdateOptions = city.UpdateOptions
+
+// WatchOptions configures Watch
+type WatchOptions = city.WatchOptions
+
+// DatasetUpdate describes the result of an update check
+type DatasetUpdate = city.DatasetUpdate
+
+// Dataset update defaults
+const (
+	DefaultMaxUpdateSize       = city.DefaultMaxUpdateSize
+	DefaultMaxDecompressedSize = city.DefaultMaxDecompressedSize
+	DefaultWatchInterval       = city.DefaultWatchInterval
+)
+
+// DefaultUpdateOptions returns the default update configuration. A checksum
+// or public key must still be set.
+func DefaultUpdateOptions() UpdateOptions {
+	return city.DefaultUpdateOptions()
+}
+
+// UpdateDatasetFromURL downloads a dataset in the cityMap.json format from
+// url, verifies its checksum or signature, validates it and atomically
+// replaces the default client's dataset with it
+func UpdateDatasetFromURL(ctx context.Context, url string, options UpdateOptions) (DatasetUpdate, error) {
+	return city.UpdateDatasetFromURL(ctx, url, options)
+}
+
+// Watch keeps the default client's dataset updated from url, checking at
+// every interval until ctx is cancelled
+func Watch(ctx context.Context, url string, options WatchOptions) error {
+	return city.Watch(ctx, url, options)
+}
+
 // CacheStats contains cache performance statistics
 type CacheStats = city.CacheStats
 
+// ResultSizeBucket is a bucket of the cache's result size histogram
+type ResultSizeBucket = city.ResultSizeBucket
+
 // ClearCache clears the global search cache
 func ClearCache() {
 	city.ClearCache()
@@ -1,6 +1,8 @@
 package citytimezones
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -234,3 +236,115 @@ func TestPublicAPI_Cache(t *testing.T) {
 		th.AssertEqual(0.0, stats.HitRate, "CacheStats should have HitRate field")
 	})
 }
+
+func TestPublicAPI_Client(t *testing.T) {
+	th := NewTestHelper(t)
+
+	t.Run("Custom normalization pipeline", func(t *testing.T) {
+		client := NewClient(ClientOptions{Normalization: []NormalizationStage{
+			LowercaseStage(),
+			FoldDiacriticsStage(),
+		}})
+
+		cities, err := client.LookupViaCity("Krakow")
+		th.AssertNoError(err, "should not error")
+		th.AssertEqual(1, len(cities), "should match Kraków without diacritics")
+	})
+
+	t.Run("Default client", func(t *testing.T) {
+		th.AssertEqual(StageLowercase, Normalization()[0].Name, "default pipeline should lowercase")
+		th.AssertEqual(true, DefaultClient() == DefaultClient(), "default client should be shared")
+	})
+}
+
+func TestPublicAPI_TypedErrors(t *testing.T) {
+	th := NewTestHelper(t)
+
+	t.Run("Distinguish bad input from no results", func(t *testing.T) {
+		client := NewClient(ClientOptions{ErrorOnNotFound: true})
+
+		_, err := client.LookupViaCity("<script>alert('xss')</script>")
+		th.AssertEqual(true, errors.Is(err, ErrInvalidInput), "should be invalid input")
+
+		_, err = client.LookupViaCity("NonExistentCity")
+		th.AssertEqual(true, errors.Is(err, ErrNotFound), "should be not found")
+
+		_, err = client.FindFromIsoCode("1X")
+		th.AssertEqual(true, errors.Is(err, ErrInvalidISOCode), "should be invalid ISO code")
+	})
+}
+
+func TestPublicAPI_Grouping(t *testing.T) {
+	th := NewTestHelper(t)
+
+	cities, err := FindFromIsoCode("US")
+	th.AssertNoError(err, "should not error")
+
+	groups := SortedGroups(GroupByTimezone(cities))
+	th.AssertEqual(true, len(groups) > 1, "US cities should span several timezones")
+	for i := 1; i < len(groups); i++ {
+		th.AssertEqual(true, groups[i-1].Key < groups[i].Key, "groups should be sorted by key")
+	}
+
+	total := 0
+	for _, group := range groups {
+		total += len(group.Cities)
+	}
+	th.AssertEqual(len(cities), total, "every city should be grouped once")
+}
+
+func TestPublicAPI_CitiesInSameTimezone(t *testing.T) {
+	th := NewTestHelper(t)
+
+	cities, err := CitiesInSameTimezone("Chicago", 3)
+	th.AssertNoError(err, "should not error")
+	th.AssertEqual(3, len(cities), "should honor the limit")
+	for _, city := range cities {
+		th.AssertEqual("America/Chicago", city.Timezone, "cities should share the timezone")
+	}
+}
+
+func TestPublicAPI_ValidateTimezones(t *testing.T) {
+	th := NewTestHelper(t)
+
+	issues, err := ValidateTimezones()
+	th.AssertNoError(err, "should not error")
+	for _, issue := range issues {
+		th.AssertNoError(issue.Err, "bundled zones should load")
+	}
+
+	th.AssertEqual("Asia/Yangon", CanonicalTimezone("Asia/Rangoon"), "legacy names should map to current names")
+	_, err = LoadLocation("Asia/Rangoon")
+	th.AssertNoError(err, "legacy names should load")
+}
+
+func TestPublicAPI_ConfigSnapshot(t *testing.T) {
+	th := NewTestHelper(t)
+
+	client := NewClient(ClientOptions{
+		Normalization:   []NormalizationStage{LowercaseStage(), StripPunctuationStage(), ExpandSynonymsStage(CommonSynonyms)},
+		ErrorOnNotFound: true,
+	})
+	snapshot, err := client.ConfigSnapshot()
+	th.AssertNoError(err, "should not error")
+
+	restored, err := NewClientFromConfig(snapshot)
+	th.AssertNoError(err, "should restore the snapshot")
+
+	cities, err := restored.LookupViaCity("St Louis")
+	th.AssertNoError(err, "should find St. Louis with the restored synonyms")
+	th.AssertEqual(true, len(cities) > 0, "should find St. Louis")
+}
+
+func TestPublicAPI_ContextVariants(t *testing.T) {
+	th := NewTestHelper(t)
+	ctx := context.Background()
+
+	cities, err := LookupViaCityContext(ctx, "Chicago")
+	th.AssertNoError(err, "should not error")
+	th.AssertEqual(true, len(cities) > 0, "should find Chicago")
+
+	cities, err = SearchCitiesContext(ctx, "chicago", DefaultSearchOptions())
+	th.AssertNoError(err, "should not error")
+	th.AssertEqual(true, len(cities) > 0, "should find Chicago")
+}
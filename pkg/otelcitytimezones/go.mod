@@ -0,0 +1,22 @@
+module github.com/richoandika/city-timezones-go/pkg/otelcitytimezones
+
+go 1.21
+
+require (
+	github.com/richoandika/city-timezones-go v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.28.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
+
+// The root module has no tagged release yet, so this module builds against
+// the checkout it is part of. Drop the replace once the root is tagged.
+replace github.com/richoandika/city-timezones-go => ../..
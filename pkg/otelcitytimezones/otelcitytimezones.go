@@ -0,0 +1,103 @@
+// Package otelcitytimezones traces city lookups with OpenTelemetry. It is a
+// separate module, so the main package does not depend on OpenTelemetry.
+//
+//	citytimezones.SetHooks(otelcitytimezones.NewHooks())
+//
+// Use the Context variants of the lookups, such as LookupViaCityContext, so
+// lookup spans become children of the caller's span.
+package otelcitytimezones
+
+import (
+	"context"
+
+	"github.com/richoandika/city-timezones-go/pkg/citytimezones"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer
+const ScopeName = "github.com/richoandika/city-timezones-go/pkg/otelcitytimezones"
+
+// Attribute keys set on lookup spans
+const (
+	FunctionKey = attribute.Key("citytimezones.function")
+	QueryKey    = attribute.Key("citytimezones.query")
+	ResultsKey  = attribute.Key("citytimezones.results")
+	CacheHitKey = attribute.Key("citytimezones.cache_hit")
+)
+
+// Option configures the hooks created by NewHooks
+type Option func(*config)
+
+type config struct {
+	provider  trace.TracerProvider
+	omitQuery bool
+}
+
+// WithTracerProvider sets the tracer provider, the global one is used by default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithoutQuery omits the query attribute, e.g. if queries contain user data
+// that must not be exported
+func WithoutQuery() Option {
+	return func(c *config) {
+		c.omitQuery = true
+	}
+}
+
+// hooks starts a span for each lookup
+type hooks struct {
+	tracer    trace.Tracer
+	omitQuery bool
+}
+
+// NewHooks returns hooks that trace each lookup as a span named
+// "citytimezones.<Function>", e.g. "citytimezones.LookupViaCity"
+func NewHooks(options ...Option) citytimezones.Hooks {
+	c := config{}
+	for _, option := range options {
+		option(&c)
+	}
+	if c.provider == nil {
+		c.provider = otel.GetTracerProvider()
+	}
+
+	return &hooks{
+		tracer:    c.provider.Tracer(ScopeName),
+		omitQuery: c.omitQuery,
+	}
+}
+
+// OnLookupStart starts the lookup's span
+func (h *hooks) OnLookupStart(ctx context.Context, event citytimezones.LookupStart) context.Context {
+	attributes := []attribute.KeyValue{FunctionKey.String(event.Function)}
+	if !h.omitQuery {
+		attributes = append(attributes, QueryKey.String(event.Query))
+	}
+
+	ctx, _ = h.tracer.Start(ctx, "citytimezones."+event.Function,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attributes...),
+	)
+	return ctx
+}
+
+// OnLookupEnd records the outcome and ends the lookup's span
+func (h *hooks) OnLookupEnd(ctx context.Context, event citytimezones.LookupEnd) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		ResultsKey.Int(event.Results),
+		CacheHitKey.Bool(event.CacheHit),
+	)
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,108 @@
+package otelcitytimezones
+
+import (
+	"context"
+	"testing"
+
+	"github.com/richoandika/city-timezones-go/pkg/citytimezones"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedClient(options ...Option) (*citytimezones.Client, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client := citytimezones.NewClient(citytimezones.ClientOptions{
+		Cities: []citytimezones.CityData{
+			{City: "Chicago", ISO2: "US", ISO3: "USA", Timezone: "America/Chicago"},
+		},
+		CacheSize: 10,
+		Hooks:     NewHooks(append(options, WithTracerProvider(provider))...),
+	})
+	return client, recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestHooks(t *testing.T) {
+	t.Run("Lookup spans", func(t *testing.T) {
+		client, recorder := newTracedClient()
+
+		_, _ = client.LookupViaCity("Chicago")
+		_, _ = client.LookupViaCity("Chicago")
+
+		spans := recorder.Ended()
+		if len(spans) != 2 {
+			t.Fatalf("Expected 2 spans, got %d", len(spans))
+		}
+
+		if spans[0].Name() != "citytimezones.LookupViaCity" {
+			t.Errorf("Unexpected span name %q", spans[0].Name())
+		}
+		values := attributes(spans[0])
+		if values[FunctionKey].AsString() != "LookupViaCity" || values[QueryKey].AsString() != "Chicago" {
+			t.Errorf("Unexpected attributes %v", values)
+		}
+		if values[ResultsKey].AsInt64() != 1 || values[CacheHitKey].AsBool() {
+			t.Errorf("Expected 1 uncached result, got %v", values)
+		}
+		if !attributes(spans[1])[CacheHitKey].AsBool() {
+			t.Error("Expected the second lookup to be a cache hit")
+		}
+	})
+
+	t.Run("Spans join the caller's trace", func(t *testing.T) {
+		client, recorder := newTracedClient()
+
+		ctx, parent := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "request")
+		_, _ = client.LookupViaCityContext(ctx, "Chicago")
+		_, _ = client.SearchCitiesContext(ctx, "chicago", citytimezones.DefaultSearchOptions())
+		parent.End()
+
+		spans := recorder.Ended()
+		if len(spans) != 2 {
+			t.Fatalf("Expected 2 spans, got %d", len(spans))
+		}
+		for _, span := range spans {
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() || span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+				t.Errorf("Expected %s to be a child of the caller's span", span.Name())
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		client, recorder := newTracedClient()
+
+		_, _ = client.FindFromIsoCode("INVALID")
+
+		spans := recorder.Ended()
+		if len(spans) != 1 {
+			t.Fatalf("Expected 1 span, got %d", len(spans))
+		}
+		if spans[0].Status().Code != codes.Error {
+			t.Errorf("Expected an error status, got %v", spans[0].Status())
+		}
+		if len(spans[0].Events()) == 0 {
+			t.Error("Expected the error to be recorded")
+		}
+	})
+
+	t.Run("Without query", func(t *testing.T) {
+		client, recorder := newTracedClient(WithoutQuery())
+
+		_, _ = client.LookupViaCity("Chicago")
+
+		if _, ok := attributes(recorder.Ended()[0])[QueryKey]; ok {
+			t.Error("Expected no query attribute")
+		}
+	})
+}
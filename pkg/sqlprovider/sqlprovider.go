@@ -0,0 +1,315 @@
+// Package sqlprovider is a reference citytimezones.DataProvider serving cities
+// from a database/sql database, such as SQLite or PostgreSQL. It is a separate
+// module, so the main package does not depend on a database driver.
+//
+//	provider := sqlprovider.New(db)
+//	if err := provider.CreateSchema(ctx); err != nil { ... }
+//	cities, _ := citytimezones.GetCityMapping()
+//	if err := provider.Import(ctx, cities); err != nil { ... }
+//
+//	client := citytimezones.NewClient(citytimezones.ClientOptions{Provider: provider})
+package sqlprovider
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/richoandika/city-timezones-go/pkg/citytimezones"
+)
+
+// nearestOversampling is the factor by which Nearest fetches more candidates
+// than requested, since the database orders them by an approximate distance
+const nearestOversampling = 4
+
+// schema creates the tables in SQL understood by both SQLite and PostgreSQL
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS cities (
+		id INTEGER PRIMARY KEY,
+		city TEXT NOT NULL,
+		city_ascii TEXT NOT NULL,
+		lat DOUBLE PRECISION NOT NULL,
+		lng DOUBLE PRECISION NOT NULL,
+		pop DOUBLE PRECISION NOT NULL,
+		country TEXT NOT NULL,
+		iso2 TEXT NOT NULL,
+		iso3 TEXT NOT NULL,
+		province TEXT NOT NULL,
+		exact_city TEXT NOT NULL,
+		exact_province TEXT NOT NULL,
+		state_ansi TEXT NOT NULL,
+		timezone TEXT NOT NULL,
+		timezone_key TEXT NOT NULL,
+		area_km2 DOUBLE PRECISION NOT NULL,
+		alt_names TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS cities_iso2 ON cities (iso2)`,
+	`CREATE INDEX IF NOT EXISTS cities_iso3 ON cities (iso3)`,
+	`CREATE INDEX IF NOT EXISTS cities_timezone_key ON cities (timezone_key)`,
+	`CREATE TABLE IF NOT EXISTS city_names (
+		name_key TEXT NOT NULL,
+		city_id INTEGER NOT NULL REFERENCES cities (id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS city_names_name_key ON city_names (name_key)`,
+}
+
+// columns are the selected columns of the cities table, in scan order
+const columns = `city, city_ascii, lat, lng, pop, country, iso2, iso3, province,
+	exact_city, exact_province, state_ansi, timezone, area_km2, alt_names`
+
+// Provider serves cities from the cities and city_names tables. It is safe
+// for concurrent use.
+type Provider struct {
+	db            *sql.DB
+	dollar        bool
+	normalization []citytimezones.NormalizationStage
+}
+
+// Option configures a Provider
+type Option func(*Provider)
+
+// WithDollarPlaceholders uses $1, $2, ... placeholders instead of ?, as
+// required by PostgreSQL drivers
+func WithDollarPlaceholders() Option {
+	return func(p *Provider) {
+		p.dollar = true
+	}
+}
+
+// WithNormalization sets the pipeline applied to city names when importing
+// and to names passed to ByName. It defaults to DefaultNormalization. Clients
+// using the provider adopt it unless ClientOptions.Normalization is set, and
+// cache ByName results under it either way.
+func WithNormalization(stages ...citytimezones.NormalizationStage) Option {
+	return func(p *Provider) {
+		p.normalization = append([]citytimezones.NormalizationStage(nil), stages...)
+	}
+}
+
+// New returns a provider reading from db
+func New(db *sql.DB, options ...Option) *Provider {
+	p := &Provider{
+		db:            db,
+		normalization: citytimezones.DefaultNormalization(),
+	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Normalization returns a copy of the provider's normalization pipeline,
+// implementing citytimezones.NameNormalizer
+func (p *Provider) Normalization() []citytimezones.NormalizationStage {
+	return append([]citytimezones.NormalizationStage(nil), p.normalization...)
+}
+
+// CreateSchema creates the provider's tables and indexes if they do not exist
+func (p *Provider) CreateSchema(ctx context.Context) error {
+	for _, statement := range schema {
+		if _, err := p.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// Import replaces the provider's cities in a single transaction. Names are
+// indexed with the provider's normalization, including alternate names.
+func (p *Provider) Import(ctx context.Context, cities []citytimezones.CityData) (err error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	for _, statement := range []string{`DELETE FROM city_names`, `DELETE FROM cities`} {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to clear cities: %w", err)
+		}
+	}
+
+	insertCity, err := tx.PrepareContext(ctx, p.rebind(`INSERT INTO cities (id, city, city_ascii, lat, lng, pop,
+		country, iso2, iso3, province, exact_city, exact_province, state_ansi, timezone, timezone_key,
+		area_km2, alt_names) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`))
+	if err != nil {
+		return err
+	}
+	defer insertCity.Close()
+
+	insertName, err := tx.PrepareContext(ctx, p.rebind(`INSERT INTO city_names (name_key, city_id) VALUES (?, ?)`))
+	if err != nil {
+		return err
+	}
+	defer insertName.Close()
+
+	for id, city := range cities {
+		altNames := ""
+		if len(city.AlternateNames) > 0 {
+			encoded, err := json.Marshal(city.AlternateNames)
+			if err != nil {
+				return err
+			}
+			altNames = string(encoded)
+		}
+
+		_, err := insertCity.ExecContext(ctx, id, city.City, city.CityASCII, city.Lat, city.Lng, city.Pop,
+			city.Country, city.ISO2, city.ISO3, city.Province, city.ExactCity, city.ExactProvince,
+			city.StateANSI, city.Timezone, strings.ToLower(city.Timezone), city.AreaKm2, altNames)
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", city.City, err)
+		}
+
+		for _, key := range p.nameKeys(city) {
+			if _, err := insertName.ExecContext(ctx, key, id); err != nil {
+				return fmt.Errorf("failed to import the names of %s: %w", city.City, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AllCities returns every city
+func (p *Provider) AllCities(ctx context.Context) ([]citytimezones.CityData, error) {
+	return p.query(ctx, `SELECT `+columns+` FROM cities ORDER BY id`)
+}
+
+// ByName returns the cities whose name or alternate name equals name after
+// the provider's normalization
+func (p *Provider) ByName(ctx context.Context, name string) ([]citytimezones.CityData, error) {
+	return p.query(ctx, `SELECT `+columns+` FROM cities
+		WHERE id IN (SELECT city_id FROM city_names WHERE name_key = ?) ORDER BY id`, p.normalize(name))
+}
+
+// ByISO returns the cities of the country with the ISO2 or ISO3 code
+func (p *Provider) ByISO(ctx context.Context, code string) ([]citytimezones.CityData, error) {
+	code = strings.ToUpper(code)
+	return p.query(ctx, `SELECT `+columns+` FROM cities WHERE iso2 = ? OR iso3 = ? ORDER BY id`, code, code)
+}
+
+// ByTimezone returns the cities in the timezone, compared case-insensitively
+func (p *Provider) ByTimezone(ctx context.Context, timezone string) ([]citytimezones.CityData, error) {
+	return p.query(ctx, `SELECT `+columns+` FROM cities WHERE timezone_key = ? ORDER BY pop DESC, id`, strings.ToLower(timezone))
+}
+
+// Nearest returns up to limit cities ordered by their great-circle distance.
+// The database preselects candidates by an equirectangular approximation,
+// which is ranked exactly afterwards. It scans the cities table; use a
+// spatial index such as PostGIS for large datasets.
+func (p *Provider) Nearest(ctx context.Context, lat, lng float64, limit int) ([]citytimezones.CityData, error) {
+	// Scale longitude differences to distances at the query's latitude. The
+	// scale is floored so candidates near the poles are still ordered.
+	scale := math.Cos(lat * math.Pi / 180)
+	scale = math.Max(scale*scale, 0.01)
+
+	candidates, err := p.query(ctx, `SELECT `+columns+` FROM (
+			SELECT `+columns+`, id, lat - ? AS dlat, ABS(lng - ?) AS dlng FROM cities
+		) AS c
+		ORDER BY dlat * dlat + (CASE WHEN dlng > 180 THEN 360 - dlng ELSE dlng END)
+			* (CASE WHEN dlng > 180 THEN 360 - dlng ELSE dlng END) * ?, id
+		LIMIT ?`, lat, lng, scale, limit*nearestOversampling+nearestOversampling)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return distanceKm(lat, lng, candidates[i]) < distanceKm(lat, lng, candidates[j])
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// query runs a query returning the selected columns of cities
+func (p *Provider) query(ctx context.Context, query string, args ...interface{}) ([]citytimezones.CityData, error) {
+	rows, err := p.db.QueryContext(ctx, p.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cities []citytimezones.CityData
+	for rows.Next() {
+		var city citytimezones.CityData
+		var altNames string
+		err := rows.Scan(&city.City, &city.CityASCII, &city.Lat, &city.Lng, &city.Pop, &city.Country,
+			&city.ISO2, &city.ISO3, &city.Province, &city.ExactCity, &city.ExactProvince, &city.StateANSI,
+			&city.Timezone, &city.AreaKm2, &altNames)
+		if err != nil {
+			return nil, err
+		}
+
+		if altNames != "" {
+			if err := json.Unmarshal([]byte(altNames), &city.AlternateNames); err != nil {
+				return nil, fmt.Errorf("invalid alternate names of %s: %w", city.City, err)
+			}
+		}
+		cities = append(cities, city)
+	}
+
+	return cities, rows.Err()
+}
+
+// rebind replaces ? placeholders with $1, $2, ... if configured
+func (p *Provider) rebind(query string) string {
+	if !p.dollar {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// normalize runs a name through the provider's normalization pipeline
+func (p *Provider) normalize(name string) string {
+	for _, stage := range p.normalization {
+		name = stage.Apply(name)
+	}
+	return name
+}
+
+// nameKeys returns the distinct normalized names of a city
+func (p *Provider) nameKeys(city citytimezones.CityData) []string {
+	keys := []string{p.normalize(city.City)}
+	for _, alt := range city.AlternateNames {
+		key := p.normalize(alt.Name)
+		if key != "" && !contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// distanceKm returns the great-circle distance in kilometers to a city
+func distanceKm(lat, lng float64, city citytimezones.CityData) float64 {
+	return citytimezones.DistanceBetween(citytimezones.CityData{Lat: lat, Lng: lng}, city)
+}
@@ -0,0 +1,155 @@
+package sqlprovider
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/richoandika/city-timezones-go/pkg/citytimezones"
+	_ "modernc.org/sqlite"
+)
+
+// newProvider returns a provider over an in-memory SQLite database holding
+// the bundled dataset
+func newProvider(t *testing.T) (*Provider, []citytimezones.CityData) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1) // Each connection has its own in-memory database
+	t.Cleanup(func() { db.Close() })
+
+	cities, err := citytimezones.GetCityMapping()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	provider := New(db)
+	if err := provider.CreateSchema(ctx); err != nil {
+		t.Fatalf("Should not error: %v", err)
+	}
+	if err := provider.Import(ctx, cities); err != nil {
+		t.Fatalf("Should not error: %v", err)
+	}
+
+	return provider, cities
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	provider, cities := newProvider(t)
+	embedded := citytimezones.NewDatasetProvider(cities)
+
+	t.Run("AllCities", func(t *testing.T) {
+		all, err := provider.AllCities(ctx)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if !reflect.DeepEqual(all, cities) {
+			t.Error("Expected the imported cities unchanged")
+		}
+	})
+
+	t.Run("Same results as the embedded provider", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			lookup func(citytimezones.DataProvider) ([]citytimezones.CityData, error)
+		}{
+			{"ByName", func(p citytimezones.DataProvider) ([]citytimezones.CityData, error) {
+				return p.ByName(ctx, "Springfield")
+			}},
+			{"ByName alternate", func(p citytimezones.DataProvider) ([]citytimezones.CityData, error) { return p.ByName(ctx, "München") }},
+			{"ByISO", func(p citytimezones.DataProvider) ([]citytimezones.CityData, error) { return p.ByISO(ctx, "deu") }},
+			{"ByTimezone", func(p citytimezones.DataProvider) ([]citytimezones.CityData, error) {
+				return p.ByTimezone(ctx, "america/chicago")
+			}},
+			{"Nearest", func(p citytimezones.DataProvider) ([]citytimezones.CityData, error) {
+				return p.Nearest(ctx, 41.88, -87.63, 5)
+			}},
+			{"Nearest across the antimeridian", func(p citytimezones.DataProvider) ([]citytimezones.CityData, error) {
+				return p.Nearest(ctx, -17, -179.9, 3)
+			}},
+			{"Nearest near the pole", func(p citytimezones.DataProvider) ([]citytimezones.CityData, error) {
+				return p.Nearest(ctx, 85, 20, 3)
+			}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				expected, err := tt.lookup(embedded)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := tt.lookup(provider)
+				if err != nil {
+					t.Fatalf("Should not error: %v", err)
+				}
+				if len(expected) == 0 {
+					t.Fatal("Expected results from the embedded provider")
+				}
+				if !reflect.DeepEqual(got, expected) {
+					t.Errorf("Expected %d cities %v, got %d %v", len(expected), names(expected), len(got), names(got))
+				}
+			})
+		}
+	})
+
+	t.Run("Client", func(t *testing.T) {
+		client := citytimezones.NewClient(citytimezones.ClientOptions{Provider: provider})
+
+		results, err := client.LookupViaCity("Chicago")
+		if err != nil || len(results) != 1 || results[0].Timezone != "America/Chicago" {
+			t.Errorf("Expected Chicago, got %v, %v", results, err)
+		}
+
+		results, err = client.FindCities(citytimezones.CityQuery{City: "Springfield", Province: "Missouri"})
+		if err != nil || len(results) != 1 {
+			t.Errorf("Expected 1 Springfield, got %d, %v", len(results), err)
+		}
+	})
+
+	t.Run("Clients adopt the provider's normalization", func(t *testing.T) {
+		provider := New(nil, WithNormalization(citytimezones.LowercaseStage()))
+		client := citytimezones.NewClient(citytimezones.ClientOptions{Provider: provider})
+
+		stages := client.Normalization()
+		if len(stages) != 1 || stages[0].Name != citytimezones.StageLowercase {
+			t.Errorf("Expected the provider's lowercase stage, got %d stages", len(stages))
+		}
+	})
+
+	t.Run("Import replaces the cities", func(t *testing.T) {
+		provider, _ := newProvider(t)
+		if err := provider.Import(ctx, cities[:10]); err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if all, _ := provider.AllCities(ctx); len(all) != 10 {
+			t.Errorf("Expected 10 cities, got %d", len(all))
+		}
+	})
+}
+
+func TestRebind(t *testing.T) {
+	query := `SELECT city FROM cities WHERE iso2 = ? OR iso3 = ?`
+
+	if got := New(nil).rebind(query); got != query {
+		t.Errorf("Expected the query unchanged, got %s", got)
+	}
+
+	expected := `SELECT city FROM cities WHERE iso2 = $1 OR iso3 = $2`
+	if got := New(nil, WithDollarPlaceholders()).rebind(query); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func names(cities []citytimezones.CityData) []string {
+	var names []string
+	for _, city := range cities {
+		names = append(names, city.City+" ("+city.ISO2+")")
+	}
+	return names
+}
@@ -0,0 +1,154 @@
+package tzboundary
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// feature is a GeoJSON feature of a timezone boundary file
+type feature struct {
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+// LoadFile loads boundaries from a GeoJSON file, or from the first .json or
+// .geojson file of a .zip archive as published by timezone-boundary-builder
+func LoadFile(path string) (*Boundaries, error) {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		return loadZip(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// loadZip loads boundaries from the first GeoJSON file of a zip archive
+func loadZip(path string) (*Boundaries, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		ext := strings.ToLower(filepath.Ext(file.Name))
+		if ext != ".json" && ext != ".geojson" {
+			continue
+		}
+
+		r, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		return Load(r)
+	}
+
+	return nil, fmt.Errorf("%s contains no GeoJSON file", path)
+}
+
+// Load loads boundaries from a GeoJSON FeatureCollection whose features have
+// Polygon or MultiPolygon geometries and the timezone in their "tzid"
+// property. Features are decoded one at a time, so large files are not held
+// in memory twice.
+func Load(r io.Reader) (*Boundaries, error) {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	b := &Boundaries{}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+		}
+
+		if key != "features" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, err
+		}
+		for dec.More() {
+			var f feature
+			if err := dec.Decode(&f); err != nil {
+				return nil, fmt.Errorf("invalid GeoJSON feature: %w", err)
+			}
+			if err := b.addFeature(f); err != nil {
+				return nil, err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(b.zones) == 0 {
+		return nil, fmt.Errorf("invalid GeoJSON: no timezone boundaries")
+	}
+	return b, nil
+}
+
+// expectDelim reads the next token and checks that it is the delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("invalid GeoJSON: expected %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+// addFeature adds the polygons of a feature under its timezone
+func (b *Boundaries) addFeature(f feature) error {
+	tzid, _ := f.Properties["tzid"].(string)
+	if tzid == "" {
+		return fmt.Errorf("invalid GeoJSON feature: missing tzid property")
+	}
+
+	var polygons [][][][2]float64
+	switch f.Geometry.Type {
+	case "Polygon":
+		var polygon [][][2]float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &polygon); err != nil {
+			return fmt.Errorf("invalid polygon of %s: %w", tzid, err)
+		}
+		polygons = append(polygons, polygon)
+	case "MultiPolygon":
+		if err := json.Unmarshal(f.Geometry.Coordinates, &polygons); err != nil {
+			return fmt.Errorf("invalid multipolygon of %s: %w", tzid, err)
+		}
+	default:
+		return fmt.Errorf("unsupported geometry %q of %s", f.Geometry.Type, tzid)
+	}
+
+	for _, rings := range polygons {
+		if len(rings) > 0 {
+			b.zones = append(b.zones, newPolygon(tzid, rings))
+		}
+	}
+	return nil
+}
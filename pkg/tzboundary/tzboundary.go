@@ -0,0 +1,159 @@
+// Package tzboundary resolves the timezone of coordinates with timezone
+// boundary polygons, such as the GeoJSON releases of timezone-boundary-builder
+// (https://github.com/evansiroky/timezone-boundary-builder). The boundary
+// data is not embedded; download it and load it with LoadFile. Without
+// boundary data, or for points outside every boundary, a Resolver falls back
+// to the timezone of the nearest city.
+//
+//	boundaries, err := tzboundary.LoadFile("timezones-with-oceans.geojson.zip")
+//	resolver := tzboundary.NewResolver(boundaries, nil)
+//	resolution, err := resolver.Resolve(41.88, -87.63) // America/Chicago
+package tzboundary
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/richoandika/city-timezones-go/pkg/citytimezones"
+)
+
+// nearestCandidates is the number of nearest cities checked for a timezone,
+// since a few cities in the dataset have none
+const nearestCandidates = 5
+
+// Source tells how a timezone was resolved
+type Source string
+
+const (
+	SourceBoundary    Source = "boundary"     // The point lies within a timezone boundary
+	SourceNearestCity Source = "nearest_city" // The timezone of the nearest city
+)
+
+// Boundaries holds timezone boundary polygons. It is safe for concurrent use.
+type Boundaries struct {
+	zones []polygon
+}
+
+// polygon is a timezone's polygon with its bounding box. The first ring is
+// the outer boundary, the others are holes.
+type polygon struct {
+	tzid   string
+	rings  [][][2]float64 // Positions as [longitude, latitude]
+	minLng float64
+	minLat float64
+	maxLng float64
+	maxLat float64
+}
+
+func newPolygon(tzid string, rings [][][2]float64) polygon {
+	p := polygon{
+		tzid:   tzid,
+		rings:  rings,
+		minLng: math.Inf(1),
+		minLat: math.Inf(1),
+		maxLng: math.Inf(-1),
+		maxLat: math.Inf(-1),
+	}
+	for _, position := range rings[0] {
+		p.minLng = math.Min(p.minLng, position[0])
+		p.minLat = math.Min(p.minLat, position[1])
+		p.maxLng = math.Max(p.maxLng, position[0])
+		p.maxLat = math.Max(p.maxLat, position[1])
+	}
+	return p
+}
+
+// contains reports whether the point lies inside the polygon and outside its
+// holes, using the even-odd rule over all rings
+func (p *polygon) contains(lat, lng float64) bool {
+	if lat < p.minLat || lat > p.maxLat || lng < p.minLng || lng > p.maxLng {
+		return false
+	}
+
+	inside := false
+	for _, ring := range p.rings {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			a, b := ring[i], ring[j]
+			if (a[1] > lat) != (b[1] > lat) && lng < (b[0]-a[0])*(lat-a[1])/(b[1]-a[1])+a[0] {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Lookup returns the timezone whose boundary contains the point, and false if
+// none does
+func (b *Boundaries) Lookup(lat, lng float64) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+	for i := range b.zones {
+		if b.zones[i].contains(lat, lng) {
+			return b.zones[i].tzid, true
+		}
+	}
+	return "", false
+}
+
+// Timezones returns the number of distinct timezones with boundaries
+func (b *Boundaries) Timezones() int {
+	if b == nil {
+		return 0
+	}
+	seen := make(map[string]bool)
+	for _, zone := range b.zones {
+		seen[zone.tzid] = true
+	}
+	return len(seen)
+}
+
+// Resolution is the resolved timezone of a point
+type Resolution struct {
+	Timezone string
+	Source   Source
+
+	// City is the nearest city whose timezone was used, set for SourceNearestCity
+	City citytimezones.CityData
+}
+
+// Resolver resolves timezones with boundaries, falling back to the nearest
+// city of a client
+type Resolver struct {
+	boundaries *Boundaries
+	client     *citytimezones.Client
+}
+
+// NewResolver returns a resolver using the boundaries, which may be nil to
+// always use the nearest city, and the cities of client, nil for the default
+// client
+func NewResolver(boundaries *Boundaries, client *citytimezones.Client) *Resolver {
+	if client == nil {
+		client = citytimezones.DefaultClient()
+	}
+	return &Resolver{boundaries: boundaries, client: client}
+}
+
+// Resolve returns the timezone of the point, from the boundary containing it
+// or else from the nearest city with a timezone
+func (r *Resolver) Resolve(lat, lng float64) (Resolution, error) {
+	if err := citytimezones.ValidateCoordinates(lat, lng); err != nil {
+		return Resolution{}, err
+	}
+
+	if tzid, ok := r.boundaries.Lookup(lat, lng); ok {
+		return Resolution{Timezone: tzid, Source: SourceBoundary}, nil
+	}
+
+	cities, err := r.client.NearestCities(lat, lng, nearestCandidates)
+	if err != nil {
+		return Resolution{}, err
+	}
+	for _, city := range cities {
+		if city.Timezone != "" {
+			return Resolution{Timezone: city.Timezone, Source: SourceNearestCity, City: city}, nil
+		}
+	}
+
+	return Resolution{}, fmt.Errorf("no city with a timezone near %g,%g: %w", lat, lng, citytimezones.ErrNotFound)
+}
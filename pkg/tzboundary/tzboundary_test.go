@@ -0,0 +1,186 @@
+package tzboundary
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richoandika/city-timezones-go/pkg/citytimezones"
+)
+
+// testBoundaries has a square zone with a hole covered by a second zone, and
+// a multipolygon zone of two squares. Positions are [longitude, latitude].
+const testBoundaries = `{
+	"type": "FeatureCollection",
+	"name": "test",
+	"features": [
+		{
+			"type": "Feature",
+			"properties": {"tzid": "Test/Outer"},
+			"geometry": {"type": "Polygon", "coordinates": [
+				[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+				[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+			]}
+		},
+		{
+			"type": "Feature",
+			"properties": {"tzid": "Test/Hole"},
+			"geometry": {"type": "Polygon", "coordinates": [
+				[[4, 4, 100], [6, 4, 100], [6, 6, 100], [4, 6, 100], [4, 4, 100]]
+			]}
+		},
+		{
+			"type": "Feature",
+			"properties": {"tzid": "Test/Islands"},
+			"geometry": {"type": "MultiPolygon", "coordinates": [
+				[[[20, 0], [22, 0], [22, 2], [20, 2], [20, 0]]],
+				[[[30, 0], [32, 0], [31, 2], [30, 0]]]
+			]}
+		}
+	]
+}`
+
+func TestLoad(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		b, err := Load(strings.NewReader(testBoundaries))
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if b.Timezones() != 3 {
+			t.Errorf("Expected 3 timezones, got %d", b.Timezones())
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		tests := map[string]string{
+			"Not an object":        `[]`,
+			"No features":          `{"type": "FeatureCollection", "features": []}`,
+			"Missing tzid":         `{"features": [{"properties": {}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 1]]]}}]}`,
+			"Unsupported geometry": `{"features": [{"properties": {"tzid": "X"}, "geometry": {"type": "Point", "coordinates": [0, 0]}}]}`,
+			"Truncated":            testBoundaries[:200],
+		}
+		for name, input := range tests {
+			t.Run(name, func(t *testing.T) {
+				if _, err := Load(strings.NewReader(input)); err == nil {
+					t.Error("Expected an error")
+				}
+			})
+		}
+	})
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	plain := filepath.Join(dir, "timezones.geojson")
+	if err := os.WriteFile(plain, []byte(testBoundaries), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(dir, "timezones.geojson.zip")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("combined.json")
+	_, _ = w.Write([]byte(testBoundaries))
+	_ = zw.Close()
+	_ = f.Close()
+
+	for _, path := range []string{plain, archive} {
+		b, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("%s: should not error: %v", filepath.Base(path), err)
+		}
+		if tzid, _ := b.Lookup(1, 1); tzid != "Test/Outer" {
+			t.Errorf("%s: expected Test/Outer, got %q", filepath.Base(path), tzid)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	b, err := Load(strings.NewReader(testBoundaries))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		lat, lng float64
+		expected string
+	}{
+		{"Inside", 2, 2, "Test/Outer"},
+		{"Inside the hole", 5, 5, "Test/Hole"},
+		{"First polygon of a multipolygon", 1, 21, "Test/Islands"},
+		{"Second polygon of a multipolygon", 0.5, 31, "Test/Islands"},
+		{"Inside the bounding box only", 1.9, 30.1, ""},
+		{"Outside", 50, 50, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tzid, ok := b.Lookup(tt.lat, tt.lng)
+			if tzid != tt.expected || ok != (tt.expected != "") {
+				t.Errorf("Expected %q, got %q (%t)", tt.expected, tzid, ok)
+			}
+		})
+	}
+}
+
+func TestResolver(t *testing.T) {
+	b, err := Load(strings.NewReader(testBoundaries))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := citytimezones.NewClient(citytimezones.ClientOptions{Cities: []citytimezones.CityData{
+		{City: "Research Station", Lat: 49.9, Lng: 49.9},
+		{City: "Nearby", Lat: 49, Lng: 49, Timezone: "Test/Nearby"},
+	}})
+
+	t.Run("Boundary", func(t *testing.T) {
+		resolution, err := NewResolver(b, client).Resolve(5, 5)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if resolution.Timezone != "Test/Hole" || resolution.Source != SourceBoundary {
+			t.Errorf("Expected Test/Hole from the boundary, got %+v", resolution)
+		}
+	})
+
+	t.Run("Nearest city outside the boundaries", func(t *testing.T) {
+		resolution, err := NewResolver(b, client).Resolve(50, 50)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		// The nearest city has no timezone, so the next one is used
+		if resolution.Timezone != "Test/Nearby" || resolution.Source != SourceNearestCity || resolution.City.City != "Nearby" {
+			t.Errorf("Expected Test/Nearby from the nearest city, got %+v", resolution)
+		}
+	})
+
+	t.Run("Nearest city without boundaries", func(t *testing.T) {
+		resolution, err := NewResolver(nil, nil).Resolve(41.88, -87.63)
+		if err != nil {
+			t.Fatalf("Should not error: %v", err)
+		}
+		if resolution.Timezone != "America/Chicago" || resolution.Source != SourceNearestCity {
+			t.Errorf("Expected America/Chicago from the nearest city, got %+v", resolution)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := NewResolver(b, client).Resolve(91, 0); !errors.Is(err, citytimezones.ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput, got %v", err)
+		}
+
+		empty := citytimezones.NewClient(citytimezones.ClientOptions{Cities: []citytimezones.CityData{}})
+		if _, err := NewResolver(nil, empty).Resolve(0, 0); !errors.Is(err, citytimezones.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+// Command gendata generates the compressed datasets embedded by the data
+// package from data/cityMap.json, and the dataset metadata in
+// data/metadata.json. Run it through go generate:
+//
+//	go generate ./data
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/richoandika/city-timezones-go/data"
+)
+
+func main() {
+	dir := flag.String("dir", "data", "Data directory containing cityMap.json")
+	flag.Parse()
+
+	if err := run(*dir); err != nil {
+		fmt.Fprintf(os.Stderr, "gendata: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string) error {
+	source, err := os.ReadFile(filepath.Join(dir, "cityMap.json"))
+	if err != nil {
+		return err
+	}
+
+	var records []json.RawMessage
+	if err := json.Unmarshal(source, &records); err != nil {
+		return fmt.Errorf("failed to parse cityMap.json: %w", err)
+	}
+
+	lite := make([]json.RawMessage, 0, len(records))
+	for _, record := range records {
+		var city struct {
+			Pop float64 `json:"pop"`
+		}
+		if err := json.Unmarshal(record, &city); err != nil {
+			return fmt.Errorf("failed to parse city %s: %w", record, err)
+		}
+		if city.Pop >= data.LiteMinPopulation {
+			lite = append(lite, record)
+		}
+	}
+
+	if err := writeGzipJSON(filepath.Join(dir, "cityMap.json.gz"), records); err != nil {
+		return err
+	}
+	if err := writeGzipJSON(filepath.Join(dir, "cityMapLite.json.gz"), lite); err != nil {
+		return err
+	}
+
+	metadata := data.Metadata{
+		Version:           datasetVersion(source),
+		Commit:            sourceCommit(dir),
+		Source:            "city-timezones by Kevin Roberts",
+		SourceURL:         "https://github.com/kevinroberts/city-timezones",
+		GeneratedAt:       time.Now().UTC().Truncate(time.Second),
+		Cities:            len(records),
+		LiteCities:        len(lite),
+		LiteMinPopulation: data.LiteMinPopulation,
+	}
+	if err := writeMetadata(filepath.Join(dir, "metadata.json"), metadata); err != nil {
+		return err
+	}
+
+	fmt.Printf("gendata: dataset %s, %d cities, %d in the lite dataset\n", metadata.Version, len(records), len(lite))
+	return nil
+}
+
+// writeGzipJSON writes the records as a compact, gzip-compressed JSON array.
+// The gzip header has no timestamp, so unchanged data produces identical files.
+func writeGzipJSON(path string, records []json.RawMessage) error {
+	var compact bytes.Buffer
+	compact.WriteByte('[')
+	for i, record := range records {
+		if i > 0 {
+			compact.WriteByte(',')
+		}
+		if err := json.Compact(&compact, record); err != nil {
+			return err
+		}
+	}
+	compact.WriteByte(']')
+
+	var out bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&out, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := zw.Write(compact.Bytes()); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, out.Bytes(), 0o644)
+}
+
+// datasetVersion identifies a revision of the dataset by the hash of its
+// source, so it only changes when the data does
+func datasetVersion(source []byte) string {
+	sum := sha256.Sum256(source)
+	return hex.EncodeToString(sum[:6])
+}
+
+// sourceCommit returns the git commit the dataset is generated from, with a
+// "-dirty" suffix if cityMap.json has uncommitted changes, or "" outside a
+// git checkout
+func sourceCommit(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	commit := strings.TrimSpace(string(out))
+
+	status, err := exec.Command("git", "-C", dir, "status", "--porcelain", "cityMap.json").Output()
+	if err == nil && len(bytes.TrimSpace(status)) > 0 {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+// writeMetadata writes the dataset metadata as indented JSON
+func writeMetadata(path string, metadata data.Metadata) error {
+	out, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0o644)
+}